@@ -0,0 +1,237 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+// Circuit breaker states.
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerOptions configures a CircuitBreaker.
+type CircuitBreakerOptions struct {
+	// ConsecutiveFailures opens the circuit after this many failures in a row, 5 by default.
+	ConsecutiveFailures int
+	// FailureRate opens the circuit when the share of failed requests in Window reaches
+	// it, e.g. 0.5. Zero disables the rate check.
+	FailureRate float64
+	MinRequests int           // 计算失败率所需的最少请求数，默认 20
+	Window      time.Duration // 失败率统计窗口，默认 1 分钟
+	// OpenTimeout is how long the circuit stays open before probing, 30 seconds by default.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of concurrent probes allowed while half-open, 1 by default.
+	HalfOpenRequests int
+	// IsFailure decides which errors count as failures; IsRetryableError is used when nil,
+	// so that client errors such as 400 or 404 do not open the circuit. Cancellations
+	// and expired deadlines of the caller's context never count as failures.
+	IsFailure func(err error) bool
+	// OnStateChange is called after every state transition.
+	OnStateChange func(from, to CircuitState)
+}
+
+// CircuitBreaker stops sending requests while the platform is failing. Requests are
+// rejected with a *CircuitOpenError while the circuit is open; after OpenTimeout a
+// few probes are let through and the circuit closes again when they succeed.
+type CircuitBreaker struct {
+	opts CircuitBreakerOptions
+	now  func() time.Time
+
+	mu          sync.Mutex
+	state       CircuitState
+	generation  uint64 // 每次状态变化加一，用于识别过期的 ticket
+	consecutive int
+	requests    int
+	failures    int
+	windowStart time.Time
+	openedAt    time.Time
+	probes      int
+}
+
+// NewCircuitBreaker returns a closed circuit breaker.
+func NewCircuitBreaker(opts CircuitBreakerOptions) *CircuitBreaker {
+	if opts.ConsecutiveFailures <= 0 {
+		opts.ConsecutiveFailures = 5
+	}
+	if opts.MinRequests <= 0 {
+		opts.MinRequests = 20
+	}
+	if opts.Window <= 0 {
+		opts.Window = time.Minute
+	}
+	if opts.OpenTimeout <= 0 {
+		opts.OpenTimeout = 30 * time.Second
+	}
+	if opts.HalfOpenRequests <= 0 {
+		opts.HalfOpenRequests = 1
+	}
+	if opts.IsFailure == nil {
+		opts.IsFailure = IsRetryableError
+	}
+	return &CircuitBreaker{opts: opts, now: time.Now}
+}
+
+// State returns the current state.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.opts.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// CircuitTicket identifies a request let through by Allow; pass it to Record.
+type CircuitTicket struct {
+	generation uint64
+}
+
+// Allow reports whether a request may be sent. It returns a *CircuitOpenError while
+// the circuit is open or all half-open probes are in flight. Every allowed request
+// must be followed by a call to Record with the returned ticket.
+func (b *CircuitBreaker) Allow() (CircuitTicket, error) {
+	b.mu.Lock()
+	now := b.now()
+	from := b.state
+	if b.state == CircuitOpen && now.Sub(b.openedAt) >= b.opts.OpenTimeout {
+		b.state = CircuitHalfOpen
+		b.generation++
+		b.probes = 0
+	}
+
+	var err error
+	switch b.state {
+	case CircuitOpen:
+		err = &CircuitOpenError{State: CircuitOpen, RetryAt: b.openedAt.Add(b.opts.OpenTimeout)}
+	case CircuitHalfOpen:
+		if b.probes >= b.opts.HalfOpenRequests {
+			err = &CircuitOpenError{State: CircuitHalfOpen}
+		} else {
+			b.probes++
+		}
+	}
+	to := b.state
+	ticket := CircuitTicket{generation: b.generation}
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return ticket, err
+}
+
+// Record reports the outcome of a request allowed by Allow. Outcomes of requests
+// allowed before the last state change are ignored, so that requests still in flight
+// when the circuit opened are not mistaken for half-open probes.
+func (b *CircuitBreaker) Record(ticket CircuitTicket, err error) {
+	canceled := errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+	failed := err != nil && !canceled && b.opts.IsFailure(err)
+
+	b.mu.Lock()
+	if ticket.generation != b.generation {
+		b.mu.Unlock()
+		return
+	}
+	now := b.now()
+	from := b.state
+
+	switch b.state {
+	case CircuitHalfOpen:
+		b.probes--
+		if canceled {
+			break // 探测被调用方取消，释放名额等待下一个探测
+		}
+		if failed {
+			b.open(now)
+		} else {
+			b.reset(now)
+			b.state = CircuitClosed
+			b.generation++
+		}
+	case CircuitClosed:
+		if canceled {
+			break
+		}
+		if now.Sub(b.windowStart) > b.opts.Window {
+			b.requests, b.failures, b.windowStart = 0, 0, now
+		}
+		b.requests++
+		if failed {
+			b.failures++
+			b.consecutive++
+		} else {
+			b.consecutive = 0
+		}
+		if b.consecutive >= b.opts.ConsecutiveFailures ||
+			(b.opts.FailureRate > 0 && b.requests >= b.opts.MinRequests &&
+				float64(b.failures)/float64(b.requests) >= b.opts.FailureRate) {
+			b.open(now)
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+func (b *CircuitBreaker) open(now time.Time) {
+	b.reset(now)
+	b.state = CircuitOpen
+	b.generation++
+	b.openedAt = now
+}
+
+func (b *CircuitBreaker) reset(now time.Time) {
+	b.consecutive, b.requests, b.failures, b.probes = 0, 0, 0, 0
+	b.windowStart = now
+}
+
+func (b *CircuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.opts.OnStateChange != nil {
+		b.opts.OnStateChange(from, to)
+	}
+}
+
+// CircuitOpenError is returned without sending the request while the circuit is open.
+type CircuitOpenError struct {
+	State   CircuitState
+	RetryAt time.Time // 预计开始半开探测的时间，半开状态下为零值
+}
+
+// Error implements the error interface.
+func (e *CircuitOpenError) Error() string {
+	if e.RetryAt.IsZero() {
+		return fmt.Sprintf("circuit breaker %s: request rejected", e.State)
+	}
+	return fmt.Sprintf("circuit breaker %s: request rejected until %s", e.State, e.RetryAt.Format(time.RFC3339))
+}
+
+// Unwrap returns ErrCircuitOpen so that errors.Is(err, ErrCircuitOpen) matches.
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// CircuitBreaker returns the client's circuit breaker, or nil when it is disabled.
+func (c *Client) CircuitBreaker() *CircuitBreaker {
+	return c.breaker
+}
@@ -0,0 +1,211 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	now := time.Unix(0, 0)
+	var transitions []string
+	breaker := NewCircuitBreaker(CircuitBreakerOptions{
+		ConsecutiveFailures: 3,
+		OpenTimeout:         time.Minute,
+		OnStateChange: func(from, to CircuitState) {
+			transitions = append(transitions, from.String()+">"+to.String())
+		},
+	})
+	breaker.now = func() time.Time { return now }
+
+	failure := &APIError{StatusCode: http.StatusBadGateway}
+	for i := 0; i < 3; i++ {
+		ticket, err := breaker.Allow()
+		if err != nil {
+			t.Fatalf("attempt %d rejected: %v", i, err)
+		}
+		breaker.Record(ticket, failure)
+	}
+	if breaker.State() != CircuitOpen {
+		t.Fatalf("expected open, got %s", breaker.State())
+	}
+
+	_, err := breaker.Allow()
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected CircuitOpenError, got %v", err)
+	}
+	if !openErr.RetryAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected RetryAt %v", openErr.RetryAt)
+	}
+
+	// 半开状态只放行一个探测请求，失败后重新打开
+	now = now.Add(time.Minute)
+	probe, err := breaker.Allow()
+	if err != nil {
+		t.Fatalf("probe rejected: %v", err)
+	}
+	if _, err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected second probe to be rejected, got %v", err)
+	}
+	breaker.Record(probe, failure)
+	if breaker.State() != CircuitOpen {
+		t.Fatalf("expected open after failed probe, got %s", breaker.State())
+	}
+
+	now = now.Add(time.Minute)
+	probe, err = breaker.Allow()
+	if err != nil {
+		t.Fatalf("probe rejected: %v", err)
+	}
+	breaker.Record(probe, nil)
+	if breaker.State() != CircuitClosed {
+		t.Fatalf("expected closed after successful probe, got %s", breaker.State())
+	}
+
+	want := []string{"closed>open", "open>half-open", "half-open>open", "open>half-open", "half-open>closed"}
+	if len(transitions) != len(want) {
+		t.Fatalf("expected transitions %v, got %v", want, transitions)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Fatalf("expected transitions %v, got %v", want, transitions)
+		}
+	}
+}
+
+func TestCircuitBreakerFailureRate(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerOptions{
+		ConsecutiveFailures: 100,
+		FailureRate:         0.5,
+		MinRequests:         4,
+	})
+
+	outcomes := []error{nil, &APIError{StatusCode: 503}, nil, &NetworkError{Operation: "http request"}}
+	for _, err := range outcomes {
+		ticket, allowErr := breaker.Allow()
+		if allowErr != nil {
+			t.Fatalf("unexpected rejection: %v", allowErr)
+		}
+		breaker.Record(ticket, err)
+	}
+	if breaker.State() != CircuitOpen {
+		t.Fatalf("expected open at 50%% failures, got %s", breaker.State())
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerOptions{ConsecutiveFailures: 2})
+	for i := 0; i < 5; i++ {
+		ticket, _ := breaker.Allow()
+		breaker.Record(ticket, &APIError{StatusCode: http.StatusNotFound})
+	}
+	if breaker.State() != CircuitClosed {
+		t.Fatalf("expected closed, got %s", breaker.State())
+	}
+}
+
+func TestCircuitBreakerIgnoresCancellation(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerOptions{ConsecutiveFailures: 2})
+	for i := 0; i < 5; i++ {
+		ticket, _ := breaker.Allow()
+		breaker.Record(ticket, &NetworkError{Operation: "http request", Err: context.Canceled})
+		ticket, _ = breaker.Allow()
+		breaker.Record(ticket, &NetworkError{Operation: "http request", Err: context.DeadlineExceeded})
+	}
+	if breaker.State() != CircuitClosed {
+		t.Fatalf("expected closed, got %s", breaker.State())
+	}
+}
+
+func TestCircuitBreakerIgnoresStaleOutcomes(t *testing.T) {
+	now := time.Unix(0, 0)
+	breaker := NewCircuitBreaker(CircuitBreakerOptions{ConsecutiveFailures: 1, OpenTimeout: time.Minute})
+	breaker.now = func() time.Time { return now }
+
+	failure := &APIError{StatusCode: http.StatusBadGateway}
+	slow, _ := breaker.Allow()
+	fast, _ := breaker.Allow()
+	breaker.Record(fast, failure)
+
+	now = now.Add(time.Minute)
+	probe, err := breaker.Allow()
+	if err != nil {
+		t.Fatalf("probe rejected: %v", err)
+	}
+	// 打开之前放行的请求此时返回，不能占用或释放探测名额
+	breaker.Record(slow, nil)
+	if breaker.State() != CircuitHalfOpen {
+		t.Fatalf("expected the stale outcome to be ignored, got %s", breaker.State())
+	}
+	if _, err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the probe slot to stay taken, got %v", err)
+	}
+	breaker.Record(probe, nil)
+	if breaker.State() != CircuitClosed {
+		t.Fatalf("expected closed after successful probe, got %s", breaker.State())
+	}
+}
+
+func TestClientCircuitBreakerFailsFast(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, ClientOptions{
+		RetryConfig:    &RetryConfig{MaxRetries: 0},
+		CircuitBreaker: &CircuitBreakerOptions{ConsecutiveFailures: 2, OpenTimeout: time.Hour},
+	}, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	ctx := context.Background()
+	if err := client.Init(ctx); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	params := ObjectSearchRecordsParams{ObjectName: "order"}
+	for i := 0; i < 2; i++ {
+		if _, err := client.Object.Search.Records(ctx, params); StatusCode(err) != http.StatusServiceUnavailable {
+			t.Fatalf("expected 503, got %v", err)
+		}
+	}
+
+	_, err := client.Object.Search.Records(ctx, params)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected the open circuit to skip the request, got %d calls", calls.Load())
+	}
+	if client.CircuitBreaker().State() != CircuitOpen {
+		t.Fatalf("expected open, got %s", client.CircuitBreaker().State())
+	}
+}
+
+func TestClientCircuitBreakerCoversAttachments(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, ClientOptions{
+		CircuitBreaker: &CircuitBreakerOptions{ConsecutiveFailures: 2, OpenTimeout: time.Hour},
+	}, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	ctx := context.Background()
+	if err := client.Init(ctx); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	params := AttachmentFileDownloadParams{FileID: "file_1"}
+	for i := 0; i < 2; i++ {
+		if _, err := client.Attachment.File.Download(ctx, params); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected a download failure, got %v", err)
+		}
+	}
+	if _, err := client.Attachment.File.Download(ctx, params); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected the open circuit to skip the download, got %d calls", calls.Load())
+	}
+}
@@ -0,0 +1,223 @@
+package apaas
+
+import (
+	"context"
+	"fmt"
+)
+
+// ObjectDeleteByQueryParams deletes all records matching a query.
+type ObjectDeleteByQueryParams struct {
+	ObjectName string
+	Data       map[string]any // records_query 参数，例如手写的 filter
+	Filter     *RecordFilter  // 可选，与 Data 中的 filter 互斥
+	// DryRun only collects the matching IDs without deleting them.
+	DryRun bool
+	// MaxRecords aborts with ErrMaxRecordsExceeded when more records match. 0 means no limit.
+	MaxRecords int
+	// Confirm is called with the number of matching records before deleting.
+	// Returning false aborts the deletion.
+	Confirm func(matched int) bool
+	Limit   int // 每批次数量，默认 100
+}
+
+// DeleteByQueryResult reports the outcome of a delete-by-query.
+type DeleteByQueryResult struct {
+	Matched int                   `json:"matched"`
+	IDs     []string              `json:"ids,omitempty"`
+	DryRun  bool                  `json:"dryRun"`
+	Aborted bool                  `json:"aborted"`
+	Result  *BatchOperationResult `json:"result,omitempty"`
+}
+
+// RecordsByQuery deletes all records matching the query. Matching IDs are collected
+// first (selecting only _id) and then removed through RecordsWithIterator.
+func (s *ObjectDeleteService) RecordsByQuery(ctx context.Context, params ObjectDeleteByQueryParams) (*DeleteByQueryResult, error) {
+	ctx = withBulkPriority(ctx)
+
+	ids, err := s.client.Object.Search.collectIDs(ctx, params.ObjectName, params.Data, params.Filter, params.MaxRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DeleteByQueryResult{Matched: len(ids), DryRun: params.DryRun}
+	s.client.log(LoggerLevelInfo, "[object.delete.recordsByQuery] Matched %d records in: %s", len(ids), params.ObjectName)
+
+	if params.DryRun {
+		result.IDs = ids
+		return result, nil
+	}
+	if len(ids) == 0 {
+		result.Result = &BatchOperationResult{Total: 0, Success: []OperationItem{}, Failed: []OperationItem{}}
+		return result, nil
+	}
+	if params.Confirm != nil && !params.Confirm(len(ids)) {
+		s.client.log(LoggerLevelWarn, "[object.delete.recordsByQuery] Deletion aborted by confirmation callback: %s", params.ObjectName)
+		result.Aborted = true
+		return result, nil
+	}
+
+	result.Result, err = s.RecordsWithIterator(ctx, ObjectDeleteRecordsIteratorParams{
+		ObjectName: params.ObjectName,
+		IDs:        ids,
+		Limit:      params.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ObjectUpdateByQueryParams updates all records matching a query.
+type ObjectUpdateByQueryParams struct {
+	ObjectName string
+	Data       map[string]any // records_query 参数，例如手写的 filter
+	Filter     *RecordFilter  // 可选，与 Data 中的 filter 互斥
+	// Patch is applied to every matching record.
+	Patch map[string]any
+	// Transform returns the fields to update for a matching record; nil or empty skips it.
+	// Used instead of Patch; the record contains the fields listed in Select.
+	Transform func(record map[string]any) (map[string]any, error)
+	Select    []string // Transform 需要读取的字段，默认只读取 _id
+	Limit     int      // 每批次数量，默认 100
+	// OnProgress is called after each batch of matching records has been written.
+	OnProgress func(progress UpdateByQueryProgress)
+}
+
+// UpdateByQueryProgress is the running state of an update-by-query. Matched is the
+// number of records the query returned before the first update.
+type UpdateByQueryProgress struct {
+	Matched   int `json:"matched"`
+	Skipped   int `json:"skipped"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// RecordsByQuery collects the records matching the query first and then applies
+// Patch or Transform to them in batches through RecordsWithIterator, so that updates
+// to filtered fields cannot shift later pages of the query. Failures of single
+// records or batches are collected in the combined result; query errors abort.
+func (s *ObjectUpdateService) RecordsByQuery(ctx context.Context, params ObjectUpdateByQueryParams) (*BatchOperationResult, error) {
+	ctx = withBulkPriority(ctx)
+
+	if (len(params.Patch) == 0) == (params.Transform == nil) {
+		return nil, fmt.Errorf("exactly one of Patch and Transform is required")
+	}
+	if _, ok := params.Patch["_id"]; ok {
+		return nil, fmt.Errorf("patch must not contain _id")
+	}
+
+	selectFields := []string{"_id"}
+	for _, field := range params.Select {
+		if field != "_id" {
+			selectFields = append(selectFields, field)
+		}
+	}
+	records, err := s.client.Object.Search.collectRecords(ctx, params.ObjectName, params.Data, params.Filter, selectFields, 0)
+	if err != nil {
+		return nil, err
+	}
+	s.client.log(LoggerLevelInfo, "[object.update.recordsByQuery] Matched %d records in: %s", len(records), params.ObjectName)
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	result := &BatchOperationResult{Success: []OperationItem{}, Failed: []OperationItem{}}
+	progress := UpdateByQueryProgress{Matched: len(records)}
+	tracker := newProgressTracker(ctx, "object.update.recordsByQuery")
+	tracker.setTotal(len(records), 0)
+	for start := 0; start < len(records); start += limit {
+		end := min(start+limit, len(records))
+
+		updates := make([]map[string]any, 0, end-start)
+		for _, record := range records[start:end] {
+			id := recordID(record)
+			fields := params.Patch
+			if params.Transform != nil {
+				fields, err = params.Transform(cloneMap(record))
+				if err != nil {
+					result.Failed = append(result.Failed, OperationItem{ID: id, Success: false, Error: err.Error()})
+					continue
+				}
+			}
+			if len(fields) == 0 {
+				progress.Skipped++
+				continue
+			}
+
+			update := cloneMap(fields)
+			update["_id"] = id
+			updates = append(updates, update)
+		}
+
+		if len(updates) > 0 {
+			batch, err := s.RecordsWithIterator(withoutProgress(ctx), ObjectUpdateRecordsIteratorParams{
+				ObjectName: params.ObjectName,
+				Records:    updates,
+				Limit:      limit,
+			})
+			if err != nil {
+				return nil, err
+			}
+			result.Success = append(result.Success, batch.Success...)
+			result.Failed = append(result.Failed, batch.Failed...)
+		}
+
+		progress.Succeeded = len(result.Success)
+		progress.Failed = len(result.Failed)
+		if params.OnProgress != nil {
+			params.OnProgress(progress)
+		}
+		tracker.step(end, progress.Succeeded, progress.Failed)
+	}
+
+	result.SuccessCount = len(result.Success)
+	result.FailedCount = len(result.Failed)
+	result.Total = result.SuccessCount + result.FailedCount
+	tracker.finish()
+
+	s.client.log(LoggerLevelInfo, "[object.update.recordsByQuery] Update completed: %s, matched=%d, skipped=%d, success=%d, failed=%d", params.ObjectName, progress.Matched, progress.Skipped, result.SuccessCount, result.FailedCount)
+	return result, nil
+}
+
+// collectIDs pages through records_query selecting only _id. With maxRecords > 0 it
+// fails with ErrMaxRecordsExceeded as soon as more records match.
+func (s *ObjectSearchService) collectIDs(ctx context.Context, objectName string, data map[string]any, filter *RecordFilter, maxRecords int) ([]string, error) {
+	records, err := s.collectRecords(ctx, objectName, data, filter, []string{"_id"}, maxRecords)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, recordID(record))
+	}
+	return ids, nil
+}
+
+// collectRecords reads every record matching the query with the given fields before
+// the caller modifies any of them. Records without _id are dropped.
+func (s *ObjectSearchService) collectRecords(ctx context.Context, objectName string, data map[string]any, filter *RecordFilter, selectFields []string, maxRecords int) ([]map[string]any, error) {
+	payload := cloneMap(data)
+	payload["select"] = selectFields
+
+	paginator := s.RecordsIterator(withoutProgress(ctx), ObjectRecordsIteratorParams{
+		ObjectName: objectName,
+		Data:       payload,
+		Filter:     filter,
+	}, PaginatorOptions{})
+
+	records := make([]map[string]any, 0)
+	for paginator.Next() {
+		if record := paginator.Item(); recordID(record) != "" {
+			records = append(records, record)
+		}
+		if maxRecords > 0 && len(records) > maxRecords {
+			return nil, fmt.Errorf("%w: more than %d records match in %s", ErrMaxRecordsExceeded, maxRecords, objectName)
+		}
+	}
+	if err := paginator.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,174 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newQueryDeleteTestClient serves two records_query pages of two IDs each and records deleted IDs.
+func newQueryDeleteTestClient(t *testing.T, deleted *[]any) *Client {
+	return newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		body := decodeTestBody(t, r)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/records_query"):
+			if sel := body["select"].([]any); len(sel) != 1 || sel[0] != "_id" {
+				t.Errorf("unexpected select: %v", sel)
+			}
+			if body["filter"] == nil {
+				t.Error("expected filter in query")
+			}
+			if body["page_token"] == "" {
+				writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{
+					"items":           []any{map[string]any{"_id": "1"}, map[string]any{"_id": "2"}},
+					"next_page_token": "next",
+				}})
+				return
+			}
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{
+				"items": []any{map[string]any{"_id": "3"}, map[string]any{"_id": "4"}},
+			}})
+		case r.Method == http.MethodDelete && strings.HasSuffix(r.URL.Path, "/records_batch"):
+			*deleted = append(*deleted, body["ids"].([]any)...)
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"items": []any{}}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+}
+
+func TestObjectDeleteRecordsByQuery(t *testing.T) {
+	filter := NewRecordFilter(Where("status", FilterEquals, "archived"))
+
+	t.Run("dry run", func(t *testing.T) {
+		var deleted []any
+		client := newQueryDeleteTestClient(t, &deleted)
+		result, err := client.Object.Delete.RecordsByQuery(context.Background(), ObjectDeleteByQueryParams{
+			ObjectName: "object_store", Filter: filter, DryRun: true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Matched != 4 || len(result.IDs) != 4 || result.Result != nil || len(deleted) != 0 {
+			t.Errorf("unexpected dry run result: %+v, deleted=%v", result, deleted)
+		}
+	})
+
+	t.Run("max records", func(t *testing.T) {
+		var deleted []any
+		client := newQueryDeleteTestClient(t, &deleted)
+		_, err := client.Object.Delete.RecordsByQuery(context.Background(), ObjectDeleteByQueryParams{
+			ObjectName: "object_store", Filter: filter, MaxRecords: 3,
+		})
+		if !errors.Is(err, ErrMaxRecordsExceeded) {
+			t.Errorf("expected ErrMaxRecordsExceeded, got %v", err)
+		}
+		if len(deleted) != 0 {
+			t.Errorf("expected nothing deleted, got %v", deleted)
+		}
+	})
+
+	t.Run("confirm rejected", func(t *testing.T) {
+		var deleted []any
+		client := newQueryDeleteTestClient(t, &deleted)
+		result, err := client.Object.Delete.RecordsByQuery(context.Background(), ObjectDeleteByQueryParams{
+			ObjectName: "object_store", Filter: filter,
+			Confirm: func(matched int) bool { return matched < 4 },
+		})
+		if err != nil || !result.Aborted || len(deleted) != 0 {
+			t.Errorf("expected aborted deletion, got %+v, %v, deleted=%v", result, err, deleted)
+		}
+	})
+
+	t.Run("delete in batches", func(t *testing.T) {
+		var deleted []any
+		client := newQueryDeleteTestClient(t, &deleted)
+		result, err := client.Object.Delete.RecordsByQuery(context.Background(), ObjectDeleteByQueryParams{
+			ObjectName: "object_store", Filter: filter, Limit: 3,
+			Confirm: func(matched int) bool { return true },
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(deleted) != 4 || result.Result == nil || result.Result.Total != 4 {
+			t.Errorf("unexpected result: %+v, deleted=%v", result, deleted)
+		}
+	})
+}
+
+func TestObjectUpdateRecordsByQuery(t *testing.T) {
+	var updated []any
+	queryDone := false
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		body := decodeTestBody(t, r)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/records_query"):
+			if len(updated) > 0 {
+				t.Error("expected every matching record to be read before the first update")
+			}
+			if body["page_token"] == "" {
+				writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{
+					"items":           []any{map[string]any{"_id": "1", "stock": 1}, map[string]any{"_id": "2", "stock": 0}},
+					"next_page_token": "next",
+				}})
+				return
+			}
+			queryDone = true
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{
+				"items": []any{map[string]any{"_id": "3", "stock": -1}},
+			}})
+		case r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/records_batch"):
+			if !queryDone {
+				t.Error("update sent while the query was still paging")
+			}
+			records := body["records"].([]any)
+			updated = append(updated, records...)
+			items := make([]any, 0, len(records))
+			for _, record := range records {
+				items = append(items, map[string]any{"_id": record.(map[string]any)["_id"], "success": true})
+			}
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"items": items}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	var progress []UpdateByQueryProgress
+	result, err := client.Object.Update.RecordsByQuery(context.Background(), ObjectUpdateByQueryParams{
+		ObjectName: "object_product",
+		Filter:     NewRecordFilter(Where("status", FilterEquals, "active")),
+		Select:     []string{"stock"},
+		Limit:      2,
+		Transform: func(record map[string]any) (map[string]any, error) {
+			stock := record["stock"].(float64)
+			switch {
+			case stock < 0:
+				return nil, errors.New("negative stock")
+			case stock == 0:
+				return map[string]any{"status": "sold_out"}, nil
+			}
+			return nil, nil
+		},
+		OnProgress: func(p UpdateByQueryProgress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(updated) != 1 || updated[0].(map[string]any)["_id"] != "2" || updated[0].(map[string]any)["status"] != "sold_out" {
+		t.Errorf("unexpected update payload: %v", updated)
+	}
+	if result.Total != 2 || result.SuccessCount != 1 || result.FailedCount != 1 || result.Failed[0].ID != "3" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	want := []UpdateByQueryProgress{{Matched: 3, Skipped: 1, Succeeded: 1}, {Matched: 3, Skipped: 1, Succeeded: 1, Failed: 1}}
+	if len(progress) != 2 || progress[0] != want[0] || progress[1] != want[1] {
+		t.Errorf("progress = %+v, want %+v", progress, want)
+	}
+
+	if _, err := client.Object.Update.RecordsByQuery(context.Background(), ObjectUpdateByQueryParams{ObjectName: "object_product"}); err == nil {
+		t.Error("expected error without Patch or Transform")
+	}
+}
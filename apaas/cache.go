@@ -0,0 +1,268 @@
+package apaas
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetadataCacheOptions configures the opt-in metadata cache.
+type MetadataCacheOptions struct {
+	// TTL is how long a cached entry stays valid, default 5 minutes.
+	TTL time.Duration
+	// MaxEntries bounds the number of cached entries, default 1000.
+	MaxEntries int
+}
+
+// DefaultMetadataCacheOptions returns the default metadata cache configuration.
+func DefaultMetadataCacheOptions() MetadataCacheOptions {
+	return MetadataCacheOptions{
+		TTL:        5 * time.Minute,
+		MaxEntries: 1000,
+	}
+}
+
+// CacheStats reports metadata cache effectiveness.
+type CacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
+
+// MetadataCache caches object field metadata, global options and global variables.
+// Concurrent misses for the same key share a single request.
+type MetadataCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List // front = most recently used
+	calls      map[string]*cacheCall
+	stats      CacheStats
+	now        func() time.Time
+}
+
+type cacheEntry struct {
+	key       string
+	resp      *APIResponse
+	expiresAt time.Time
+}
+
+type cacheCall struct {
+	done chan struct{}
+	resp *APIResponse
+	err  error
+}
+
+// NewMetadataCache constructs a metadata cache using the provided options.
+func NewMetadataCache(opts MetadataCacheOptions) *MetadataCache {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultMetadataCacheOptions().TTL
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultMetadataCacheOptions().MaxEntries
+	}
+
+	return &MetadataCache{
+		ttl:        opts.TTL,
+		maxEntries: opts.MaxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		calls:      make(map[string]*cacheCall),
+		now:        time.Now,
+	}
+}
+
+// Stats returns a snapshot of the cache statistics.
+func (c *MetadataCache) Stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	return stats
+}
+
+// InvalidateObject drops the cached field metadata of an object, including single-field entries.
+func (c *MetadataCache) InvalidateObject(objectName string) {
+	c.invalidatePrefix(fieldCachePrefix(objectName))
+	c.invalidate(fieldsCacheKey(objectName))
+}
+
+// InvalidateField drops the cached metadata of a single field.
+func (c *MetadataCache) InvalidateField(objectName, fieldName string) {
+	c.invalidate(fieldCacheKey(objectName, fieldName))
+}
+
+// InvalidateGlobalOption drops the cached detail of a global option.
+func (c *MetadataCache) InvalidateGlobalOption(apiName string) {
+	c.invalidate(globalOptionCacheKey(apiName))
+}
+
+// InvalidateGlobalVariable drops the cached detail of a global variable.
+func (c *MetadataCache) InvalidateGlobalVariable(apiName string) {
+	c.invalidate(globalVariableCacheKey(apiName))
+}
+
+// Purge drops all cached entries.
+func (c *MetadataCache) Purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// metadataLoadTimeout bounds a load shared by concurrent callers, which runs
+// detached from the context of the caller that started it.
+const metadataLoadTimeout = 30 * time.Second
+
+// fetch returns the cached response for key or loads it, deduplicating concurrent loads.
+// Only successful responses (code "0") are cached. A nil cache always loads.
+//
+// A shared load keeps the values of the first caller's ctx but not its cancellation,
+// so one caller giving up does not fail the others; every caller stops waiting when
+// its own ctx is done.
+func (c *MetadataCache) fetch(ctx context.Context, key string, load func(ctx context.Context) (*APIResponse, error)) (*APIResponse, error) {
+	if c == nil {
+		return load(ctx)
+	}
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if c.now().Before(entry.expiresAt) {
+			c.order.MoveToFront(elem)
+			c.stats.Hits++
+			c.mu.Unlock()
+			return copyResponse(entry.resp), nil
+		}
+		c.removeElement(elem)
+	}
+
+	call, ok := c.calls[key]
+	if ok {
+		c.stats.Hits++
+	} else {
+		c.stats.Misses++
+		call = &cacheCall{done: make(chan struct{})}
+		c.calls[key] = call
+		go c.load(ctx, key, call, load)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return nil, call.err
+		}
+		return copyResponse(call.resp), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// load runs a shared load and publishes its result to every waiting caller.
+func (c *MetadataCache) load(ctx context.Context, key string, call *cacheCall, load func(ctx context.Context) (*APIResponse, error)) {
+	loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), metadataLoadTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			call.resp, call.err = nil, fmt.Errorf("metadata load for %s panicked: %v", key, r)
+		}
+		c.mu.Lock()
+		delete(c.calls, key)
+		if call.err == nil && call.resp != nil && call.resp.Code == "0" {
+			c.store(key, call.resp)
+		}
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.resp, call.err = load(loadCtx)
+}
+
+// store must be called with c.mu held.
+func (c *MetadataCache) store(key string, resp *APIResponse) {
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+
+	elem := c.order.PushFront(&cacheEntry{key: key, resp: resp, expiresAt: c.now().Add(c.ttl)})
+	c.entries[key] = elem
+
+	for c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// removeElement must be called with c.mu held.
+func (c *MetadataCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+func (c *MetadataCache) invalidate(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+func (c *MetadataCache) invalidatePrefix(prefix string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.removeElement(elem)
+		}
+	}
+}
+
+func copyResponse(resp *APIResponse) *APIResponse {
+	if resp == nil {
+		return nil
+	}
+	clone := *resp
+	return &clone
+}
+
+func fieldsCacheKey(objectName string) string {
+	return "object.fields:" + objectName
+}
+
+func fieldCachePrefix(objectName string) string {
+	return "object.field:" + objectName + "."
+}
+
+func fieldCacheKey(objectName, fieldName string) string {
+	return fieldCachePrefix(objectName) + fieldName
+}
+
+func globalOptionCacheKey(apiName string) string {
+	return "global.option:" + apiName
+}
+
+func globalVariableCacheKey(apiName string) string {
+	return "global.variable:" + apiName
+}
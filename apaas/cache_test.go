@@ -0,0 +1,192 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMetadataCache_HitAndExpiry(t *testing.T) {
+	cache := NewMetadataCache(MetadataCacheOptions{TTL: time.Minute, MaxEntries: 10})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	loads := 0
+	load := func(context.Context) (*APIResponse, error) {
+		loads++
+		return &APIResponse{Code: "0"}, nil
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := cache.fetch(ctx, "k", load); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expected 1 load before expiry, got %d", loads)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := cache.fetch(ctx, "k", load); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loads != 2 {
+		t.Errorf("expected reload after expiry, got %d loads", loads)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 2 || stats.Entries != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestMetadataCache_Eviction(t *testing.T) {
+	cache := NewMetadataCache(MetadataCacheOptions{TTL: time.Minute, MaxEntries: 2})
+	ctx := context.Background()
+	load := func(context.Context) (*APIResponse, error) { return &APIResponse{Code: "0"}, nil }
+
+	_, _ = cache.fetch(ctx, "a", load)
+	_, _ = cache.fetch(ctx, "b", load)
+	_, _ = cache.fetch(ctx, "a", load) // a becomes most recently used
+	_, _ = cache.fetch(ctx, "c", load) // evicts b
+
+	if _, ok := cache.entries["b"]; ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	if _, ok := cache.entries["a"]; !ok {
+		t.Error("expected recently used entry to be kept")
+	}
+	if stats := cache.Stats(); stats.Evictions != 1 || stats.Entries != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestMetadataCache_ErrorsAndFailuresNotCached(t *testing.T) {
+	cache := NewMetadataCache(DefaultMetadataCacheOptions())
+	ctx := context.Background()
+
+	if _, err := cache.fetch(ctx, "k", func(context.Context) (*APIResponse, error) { return nil, errors.New("boom") }); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if _, err := cache.fetch(ctx, "k", func(context.Context) (*APIResponse, error) { return &APIResponse{Code: "k_ec_1"}, nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("expected nothing cached, got %+v", stats)
+	}
+}
+
+func TestMetadataCache_Singleflight(t *testing.T) {
+	cache := NewMetadataCache(DefaultMetadataCacheOptions())
+	ctx := context.Background()
+
+	var loads int32
+	release := make(chan struct{})
+	load := func(context.Context) (*APIResponse, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return &APIResponse{Code: "0"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.fetch(ctx, "k", load); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&loads); got != 1 {
+		t.Errorf("expected concurrent misses to share 1 load, got %d", got)
+	}
+}
+
+func TestMetadataCache_Invalidate(t *testing.T) {
+	cache := NewMetadataCache(DefaultMetadataCacheOptions())
+	ctx := context.Background()
+	load := func(context.Context) (*APIResponse, error) { return &APIResponse{Code: "0"}, nil }
+
+	_, _ = cache.fetch(ctx, fieldsCacheKey("store"), load)
+	_, _ = cache.fetch(ctx, fieldCacheKey("store", "name"), load)
+	_, _ = cache.fetch(ctx, fieldCacheKey("storefront", "name"), load)
+	_, _ = cache.fetch(ctx, globalOptionCacheKey("region"), load)
+
+	cache.InvalidateObject("store")
+	if stats := cache.Stats(); stats.Entries != 2 {
+		t.Errorf("expected 2 entries after invalidating object, got %d", stats.Entries)
+	}
+
+	cache.InvalidateGlobalOption("region")
+	if _, ok := cache.entries[globalOptionCacheKey("region")]; ok {
+		t.Error("expected global option to be invalidated")
+	}
+
+	var nilCache *MetadataCache
+	nilCache.InvalidateObject("store") // must not panic
+}
+
+func TestMetadataCache_LeaderCancellationDoesNotFailWaiters(t *testing.T) {
+	cache := NewMetadataCache(DefaultMetadataCacheOptions())
+	release := make(chan struct{})
+	load := func(ctx context.Context) (*APIResponse, error) {
+		select {
+		case <-release:
+			return &APIResponse{Code: "0"}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := cache.fetch(leaderCtx, "k", load)
+		leaderErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	waiterErr := make(chan error, 1)
+	go func() {
+		_, err := cache.fetch(context.Background(), "k", load)
+		waiterErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	cancelLeader()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the leader to stop waiting, got %v", err)
+	}
+	close(release)
+	if err := <-waiterErr; err != nil {
+		t.Fatalf("expected the waiter to get the shared result, got %v", err)
+	}
+	if stats := cache.Stats(); stats.Entries != 1 {
+		t.Errorf("expected the result to be cached, got %+v", stats)
+	}
+}
+
+func TestMetadataCache_PanickingLoadReleasesWaiters(t *testing.T) {
+	cache := NewMetadataCache(DefaultMetadataCacheOptions())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := cache.fetch(ctx, "k", func(context.Context) (*APIResponse, error) { panic("boom") })
+	if err == nil || errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the panic to be reported as an error, got %v", err)
+	}
+	resp, err := cache.fetch(ctx, "k", func(context.Context) (*APIResponse, error) { return &APIResponse{Code: "0"}, nil })
+	if err != nil || resp.Code != "0" {
+		t.Fatalf("expected the key to be loaded again, got %+v, %v", resp, err)
+	}
+}
@@ -0,0 +1,349 @@
+package apaas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Checkpoint is the persisted high-water mark of a change feed.
+type Checkpoint struct {
+	// UpdatedAt and ID identify the last emitted record; ID breaks ties between
+	// records modified in the same millisecond.
+	UpdatedAt int64  `json:"updatedAt"`
+	ID        string `json:"id"`
+	// Recent holds the versions emitted inside the overlap window, so that records
+	// re-read by overlapping queries are not emitted twice.
+	Recent map[string]int64 `json:"recent,omitempty"`
+}
+
+// CheckpointStore persists change feed checkpoints by key.
+type CheckpointStore interface {
+	// Load returns the stored checkpoint, or nil when there is none.
+	Load(ctx context.Context, key string) (*Checkpoint, error)
+	Save(ctx context.Context, key string, checkpoint Checkpoint) error
+}
+
+// MemoryCheckpointStore keeps checkpoints in memory.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryCheckpointStore returns an empty in-memory store.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]Checkpoint)}
+}
+
+// Load implements CheckpointStore.
+func (s *MemoryCheckpointStore) Load(ctx context.Context, key string) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoint, ok := s.checkpoints[key]
+	if !ok {
+		return nil, nil
+	}
+	checkpoint.Recent = cloneVersions(checkpoint.Recent)
+	return &checkpoint, nil
+}
+
+// Save implements CheckpointStore.
+func (s *MemoryCheckpointStore) Save(ctx context.Context, key string, checkpoint Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoint.Recent = cloneVersions(checkpoint.Recent)
+	s.checkpoints[key] = checkpoint
+	return nil
+}
+
+// FileCheckpointStore stores each checkpoint as a JSON file in Dir.
+type FileCheckpointStore struct {
+	Dir string
+}
+
+// Load implements CheckpointStore.
+func (s FileCheckpointStore) Load(ctx context.Context, key string) (*Checkpoint, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint %s: %w", key, err)
+	}
+	return &checkpoint, nil
+}
+
+// Save implements CheckpointStore. The file is replaced atomically.
+func (s FileCheckpointStore) Save(ctx context.Context, key string, checkpoint Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	tmp := s.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(key))
+}
+
+func (s FileCheckpointStore) path(key string) string {
+	return filepath.Join(s.Dir, url.PathEscape(key)+".checkpoint.json")
+}
+
+// ChangeKind is the type of a detected change.
+type ChangeKind string
+
+// Change kinds.
+const (
+	ChangeUpserted ChangeKind = "upserted"
+	ChangeDeleted  ChangeKind = "deleted"
+)
+
+// Change is a record created, updated or deleted since the last poll.
+type Change struct {
+	Kind      ChangeKind     `json:"kind"`
+	ID        string         `json:"id"`
+	UpdatedAt int64          `json:"updatedAt,omitempty"`
+	Record    map[string]any `json:"record,omitempty"`
+}
+
+// ChangeFeedOptions configures incremental change polling.
+type ChangeFeedOptions struct {
+	ObjectName string
+	Select     []string      // 需要读取的字段，_id 与更新时间字段会自动加入
+	Filter     *RecordFilter // 可选，仅同步满足条件的记录
+	// UpdatedAtField is the last-modified timestamp in milliseconds, "_updatedAt" by default.
+	UpdatedAtField string
+	// Store persists the checkpoint; an in-memory store is used when nil.
+	Store CheckpointStore
+	Key   string // checkpoint key, defaults to ObjectName
+	// Overlap re-reads this window before the high-water mark to catch records whose
+	// timestamp lags behind (server clock skew, late commits). Default 1 minute.
+	Overlap  time.Duration
+	PageSize int
+	// PollInterval is the delay between polls in Run, 30 seconds by default.
+	PollInterval time.Duration
+	// KnownIDs returns the IDs present downstream. When set, Run reconciles them against
+	// the object every ReconcileInterval (default 1 hour) and emits ChangeDeleted for
+	// IDs that no longer exist.
+	KnownIDs          func(ctx context.Context) ([]string, error)
+	ReconcileInterval time.Duration
+}
+
+// ChangeFeed polls an object for records modified since a persisted high-water mark.
+type ChangeFeed struct {
+	client *Client
+	opts   ChangeFeedOptions
+}
+
+// ChangeFeed returns a change feed for opts.ObjectName.
+func (s *ObjectService) ChangeFeed(opts ChangeFeedOptions) *ChangeFeed {
+	if opts.UpdatedAtField == "" {
+		opts.UpdatedAtField = DefaultVersionField
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemoryCheckpointStore()
+	}
+	if opts.Key == "" {
+		opts.Key = opts.ObjectName
+	}
+	if opts.Overlap <= 0 {
+		opts.Overlap = time.Minute
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 30 * time.Second
+	}
+	if opts.ReconcileInterval <= 0 {
+		opts.ReconcileInterval = time.Hour
+	}
+	return &ChangeFeed{client: s.client, opts: opts}
+}
+
+// Poll emits every record modified since the checkpoint, ordered by (UpdatedAt, ID),
+// and advances the checkpoint. Records are requested sorted by the same key and
+// handed to handle page by page, so only one page is held in memory. When handle
+// fails, the checkpoint is saved up to the last handled record and the error is
+// returned.
+//
+// The query starts Overlap before the high-water mark, so Checkpoint.ID is not part
+// of it; records re-read in that window are skipped through Checkpoint.Recent.
+func (f *ChangeFeed) Poll(ctx context.Context, handle func(Change) error) (int, error) {
+	ctx = withBulkPriority(ctx)
+
+	checkpoint, err := f.opts.Store.Load(ctx, f.opts.Key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if checkpoint == nil {
+		checkpoint = &Checkpoint{}
+	}
+	if checkpoint.Recent == nil {
+		checkpoint.Recent = make(map[string]int64)
+	}
+
+	filter := f.opts.Filter
+	since := checkpoint.UpdatedAt - f.opts.Overlap.Milliseconds()
+	if checkpoint.UpdatedAt > 0 {
+		filter = filter.And(NewRecordFilter(Where(f.opts.UpdatedAtField, FilterGreaterEq, since)))
+	}
+
+	selectFields := append([]string{"_id", f.opts.UpdatedAtField}, f.opts.Select...)
+	paginator := f.client.Object.Search.RecordsIterator(withoutProgress(ctx), ObjectRecordsIteratorParams{
+		ObjectName: f.opts.ObjectName,
+		Data: map[string]any{
+			"select": selectFields,
+			"order_by": []map[string]any{
+				{"field": f.opts.UpdatedAtField, "direction": "asc"},
+				{"field": "_id", "direction": "asc"},
+			},
+		},
+		Filter: filter,
+	}, PaginatorOptions{PageSize: f.opts.PageSize})
+
+	emitted := 0
+	var pollErr error
+	var lastAt int64
+	lastID := ""
+	for paginator.Next() {
+		record := paginator.Item()
+		id := recordID(record)
+		updatedAt, err := timestampMillis(record[f.opts.UpdatedAtField])
+		if id == "" || err != nil {
+			pollErr = fmt.Errorf("record %q has no valid %s: %v", id, f.opts.UpdatedAtField, record[f.opts.UpdatedAtField])
+			break
+		}
+		// 检查点依赖排序，服务端未按 (修改时间, _id) 返回时停止，避免高水位跳过记录
+		if updatedAt < lastAt || (updatedAt == lastAt && id < lastID) {
+			pollErr = fmt.Errorf("records of %s are not ordered by (%s, _id): %d/%s after %d/%s", f.opts.ObjectName, f.opts.UpdatedAtField, updatedAt, id, lastAt, lastID)
+			break
+		}
+		lastAt, lastID = updatedAt, id
+
+		if version, ok := checkpoint.Recent[id]; ok && version == updatedAt {
+			continue // 重叠窗口内已发送过
+		}
+		if err := handle(Change{Kind: ChangeUpserted, ID: id, UpdatedAt: updatedAt, Record: record}); err != nil {
+			pollErr = err
+			break
+		}
+		emitted++
+		checkpoint.Recent[id] = updatedAt
+		if updatedAt > checkpoint.UpdatedAt || (updatedAt == checkpoint.UpdatedAt && id > checkpoint.ID) {
+			checkpoint.UpdatedAt = updatedAt
+			checkpoint.ID = id
+		}
+	}
+	if pollErr == nil {
+		pollErr = paginator.Err()
+	}
+
+	cutoff := checkpoint.UpdatedAt - f.opts.Overlap.Milliseconds()
+	for id, version := range checkpoint.Recent {
+		if version < cutoff {
+			delete(checkpoint.Recent, id)
+		}
+	}
+	if err := f.opts.Store.Save(ctx, f.opts.Key, *checkpoint); err != nil {
+		return emitted, fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+
+	f.client.log(LoggerLevelInfo, "[object.changeFeed.poll] Poll completed: %s, changes=%d, highWaterMark=%d/%s", f.opts.ObjectName, emitted, checkpoint.UpdatedAt, checkpoint.ID)
+	return emitted, pollErr
+}
+
+// Reconcile compares knownIDs with the IDs currently matching the feed's filter and
+// emits ChangeDeleted for every known ID that no longer exists.
+func (f *ChangeFeed) Reconcile(ctx context.Context, knownIDs []string, handle func(Change) error) (int, error) {
+	ctx = withBulkPriority(ctx)
+
+	ids, err := f.client.Object.Search.collectIDs(ctx, f.opts.ObjectName, nil, f.opts.Filter, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	existing := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		existing[id] = struct{}{}
+	}
+
+	deleted := 0
+	for _, id := range knownIDs {
+		if _, ok := existing[id]; ok {
+			continue
+		}
+		if err := handle(Change{Kind: ChangeDeleted, ID: id}); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	f.client.log(LoggerLevelInfo, "[object.changeFeed.reconcile] Reconcile completed: %s, remote=%d, known=%d, deleted=%d", f.opts.ObjectName, len(ids), len(knownIDs), deleted)
+	return deleted, nil
+}
+
+// Run polls every PollInterval, reconciling deletions every ReconcileInterval when
+// KnownIDs is set, until ctx is done or handle fails.
+func (f *ChangeFeed) Run(ctx context.Context, handle func(Change) error) error {
+	lastReconcile := time.Now()
+	for {
+		if _, err := f.Poll(ctx, handle); err != nil {
+			return err
+		}
+
+		if f.opts.KnownIDs != nil && time.Since(lastReconcile) >= f.opts.ReconcileInterval {
+			known, err := f.opts.KnownIDs(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to load known IDs: %w", err)
+			}
+			if _, err := f.Reconcile(ctx, known, handle); err != nil {
+				return err
+			}
+			lastReconcile = time.Now()
+		}
+
+		timer := time.NewTimer(f.opts.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// timestampMillis reads a millisecond timestamp from a JSON number or numeric string.
+func timestampMillis(value any) (int64, error) {
+	switch v := value.(type) {
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	case json.Number:
+		return v.Int64()
+	}
+	if f, ok := toFloat(value); ok {
+		return int64(f), nil
+	}
+	return 0, fmt.Errorf("invalid timestamp %v", value)
+}
+
+func cloneVersions(versions map[string]int64) map[string]int64 {
+	if versions == nil {
+		return nil
+	}
+	clone := make(map[string]int64, len(versions))
+	for id, version := range versions {
+		clone[id] = version
+	}
+	return clone
+}
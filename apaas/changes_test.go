@@ -0,0 +1,186 @@
+package apaas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// changeTestTable serves records_query from an in-memory table, honoring a gte
+// condition on _updatedAt, the (_updatedAt, _id) order and page tokens.
+type changeTestTable struct {
+	mu      sync.Mutex
+	records map[string]int64 // _id -> _updatedAt
+	queries int
+}
+
+func (tbl *changeTestTable) set(id string, updatedAt int64) {
+	tbl.mu.Lock()
+	defer tbl.mu.Unlock()
+	tbl.records[id] = updatedAt
+}
+
+func (tbl *changeTestTable) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body := decodeTestBody(t, r)
+		since := int64(-1)
+		if filter, ok := body["filter"].(map[string]any); ok {
+			condition := filter["conditions"].([]any)[0].(map[string]any)
+			var right struct {
+				Data int64 `json:"data"`
+			}
+			if err := json.Unmarshal([]byte(condition["right"].(map[string]any)["settings"].(string)), &right); err != nil {
+				t.Errorf("invalid filter: %v", err)
+			}
+			since = right.Data
+		}
+
+		// Poll 需要排序，Reconcile 只读取 _id
+		if orderBy, _ := body["order_by"].([]any); len(body["select"].([]any)) > 1 && len(orderBy) != 2 {
+			t.Errorf("expected records to be ordered by (_updatedAt, _id), got %v", body["order_by"])
+		}
+
+		tbl.mu.Lock()
+		tbl.queries++
+		type row struct {
+			id        string
+			updatedAt int64
+		}
+		rows := make([]row, 0)
+		for id, updatedAt := range tbl.records {
+			if updatedAt >= since {
+				rows = append(rows, row{id, updatedAt})
+			}
+		}
+		tbl.mu.Unlock()
+		sort.Slice(rows, func(i, j int) bool {
+			if rows[i].updatedAt != rows[j].updatedAt {
+				return rows[i].updatedAt < rows[j].updatedAt
+			}
+			return rows[i].id < rows[j].id
+		})
+
+		offset, _ := strconv.Atoi(body["page_token"].(string))
+		end := min(offset+int(body["page_size"].(float64)), len(rows))
+		items := make([]any, 0)
+		for _, r := range rows[offset:end] {
+			items = append(items, map[string]any{"_id": r.id, "_updatedAt": r.updatedAt})
+		}
+		next := ""
+		if end < len(rows) {
+			next = strconv.Itoa(end)
+		}
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"items": items, "next_page_token": next}})
+	}
+}
+
+func TestChangeFeed_Poll(t *testing.T) {
+	table := &changeTestTable{records: map[string]int64{"1": 1000, "2": 2000, "3": 2000}}
+	client := newTestClient(t, ClientOptions{}, table.handler(t))
+	store := FileCheckpointStore{Dir: t.TempDir()}
+	feed := client.Object.ChangeFeed(ChangeFeedOptions{ObjectName: "object_store", Store: store, Overlap: 5 * time.Second})
+
+	poll := func() []string {
+		var got []string
+		if _, err := feed.Poll(context.Background(), func(change Change) error {
+			got = append(got, change.ID)
+			return nil
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return got
+	}
+
+	if got := poll(); len(got) != 3 || got[0] != "1" || got[1] != "2" || got[2] != "3" {
+		t.Fatalf("first poll = %v, want [1 2 3] in (updatedAt, id) order", got)
+	}
+	checkpoint, err := store.Load(context.Background(), "object_store")
+	if err != nil || checkpoint.UpdatedAt != 2000 || checkpoint.ID != "3" {
+		t.Fatalf("unexpected checkpoint: %+v, %v", checkpoint, err)
+	}
+
+	if got := poll(); len(got) != 0 {
+		t.Errorf("second poll without changes = %v", got)
+	}
+
+	// 2 is updated; 4 commits late with a timestamp behind the high-water mark
+	table.set("2", 3000)
+	table.set("4", 1500)
+	if got := poll(); len(got) != 2 || got[0] != "4" || got[1] != "2" {
+		t.Errorf("poll after changes = %v, want [4 2]", got)
+	}
+}
+
+func TestChangeFeed_PollStreamsPages(t *testing.T) {
+	table := &changeTestTable{records: map[string]int64{"1": 1000, "2": 2000, "3": 3000}}
+	client := newTestClient(t, ClientOptions{}, table.handler(t))
+	feed := client.Object.ChangeFeed(ChangeFeedOptions{ObjectName: "object_store", PageSize: 2})
+
+	var queriesAtFirst int
+	failing := errors.New("downstream unavailable")
+	count, err := feed.Poll(context.Background(), func(change Change) error {
+		if change.ID == "1" {
+			table.mu.Lock()
+			queriesAtFirst = table.queries
+			table.mu.Unlock()
+		}
+		if change.ID == "3" {
+			return failing
+		}
+		return nil
+	})
+	if !errors.Is(err, failing) || count != 2 {
+		t.Fatalf("expected the handler error after 2 changes, got %d, %v", count, err)
+	}
+	if queriesAtFirst != 1 {
+		t.Errorf("expected the first change before the second page was read, got %d queries", queriesAtFirst)
+	}
+	checkpoint, _ := feed.opts.Store.Load(context.Background(), "object_store")
+	if checkpoint.UpdatedAt != 2000 || checkpoint.ID != "2" {
+		t.Errorf("expected the checkpoint at the last handled record, got %+v", checkpoint)
+	}
+}
+
+func TestChangeFeed_PollRejectsUnorderedRecords(t *testing.T) {
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"items": []any{
+			map[string]any{"_id": "2", "_updatedAt": 2000},
+			map[string]any{"_id": "1", "_updatedAt": 1000},
+		}}})
+	})
+	feed := client.Object.ChangeFeed(ChangeFeedOptions{ObjectName: "object_store"})
+
+	var got []string
+	_, err := feed.Poll(context.Background(), func(change Change) error {
+		got = append(got, change.ID)
+		return nil
+	})
+	if err == nil || len(got) != 1 {
+		t.Fatalf("expected an ordering error after the first change, got %v, %v", got, err)
+	}
+}
+
+func TestChangeFeed_Reconcile(t *testing.T) {
+	table := &changeTestTable{records: map[string]int64{"1": 1000, "2": 2000}}
+	client := newTestClient(t, ClientOptions{}, table.handler(t))
+	feed := client.Object.ChangeFeed(ChangeFeedOptions{ObjectName: "object_store"})
+
+	var deleted []string
+	count, err := feed.Reconcile(context.Background(), []string{"1", "2", "3", "5"}, func(change Change) error {
+		if change.Kind != ChangeDeleted {
+			t.Errorf("unexpected change kind %s", change.Kind)
+		}
+		deleted = append(deleted, change.ID)
+		return nil
+	})
+	sort.Strings(deleted)
+	if err != nil || count != 2 || len(deleted) != 2 || deleted[0] != "3" || deleted[1] != "5" {
+		t.Errorf("deleted = %v (%d), err = %v", deleted, count, err)
+	}
+}
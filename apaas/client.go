@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -26,6 +27,36 @@ type ClientOptions struct {
 	Logger            Logger
 	LimiterOptions    *LimiterOptions
 	RetryConfig       *RetryConfig
+	// RetryPolicies overrides RetryConfig per operation. Keys are operation names or
+	// prefixes such as "object.create" or "automation"; the longest match wins.
+	RetryPolicies map[string]RetryConfig
+	// LimiterBuckets gives endpoint groups (LimiterBucket* constants) their own limiter.
+	// Buckets without options get their DefaultLimiterBuckets quota, capped by
+	// LimiterOptions, and keep the adaptive settings of LimiterOptions.
+	LimiterBuckets map[string]LimiterOptions
+	// ShareLimiters shares the limiters with every other client of the process using
+	// the same base URL and client ID. The options of the first client win. Such a
+	// client must be closed with Client.Close; the shared limiters stay registered
+	// until every client using them has been closed.
+	ShareLimiters bool
+	// Limiter replaces the LimiterOptions limiter, e.g. with a SharedLimiter
+	// coordinating a request budget across processes. It also applies to buckets
+	// without LimiterBuckets options instead of their default quotas.
+	Limiter Limiter
+	// BucketLimiters replace the limiters of individual buckets.
+	BucketLimiters map[string]Limiter
+	// MetadataCache enables caching of field metadata, global options and
+	// global variables. Nil disables caching.
+	MetadataCache *MetadataCacheOptions
+	// ValidateRecords checks records against field metadata before create and
+	// update requests are sent. It enables the metadata cache with default
+	// options when MetadataCache is nil.
+	ValidateRecords bool
+	// CircuitBreaker fails requests fast while the platform is failing. Nil disables it.
+	CircuitBreaker *CircuitBreakerOptions
+	// IdempotencyJournal records requests sent with WithIdempotencyKey. An in-memory
+	// journal keeping entries for 24 hours is used when nil.
+	IdempotencyJournal IdempotencyJournal
 }
 
 // Client wraps HTTP access to the aPaaS OpenAPI.
@@ -45,9 +76,18 @@ type Client struct {
 	expireTime      time.Time
 	tokenRefreshing bool // Flag to prevent concurrent token refreshes
 
-	limiter *RateLimiter
+	limiters        map[string]Limiter
+	releaseLimiters func()
+	closeOnce       sync.Once
 
-	retryConfig RetryConfig
+	retryConfig   RetryConfig
+	retryPolicies map[string]RetryConfig
+
+	metadataCache   *MetadataCache
+	validateRecords bool
+
+	idempotencyJournal IdempotencyJournal
+	breaker            *CircuitBreaker
 
 	// Service groups
 	Object     *ObjectService
@@ -111,8 +151,51 @@ func NewClient(opts ClientOptions) (*Client, error) {
 		httpClient:        httpClient,
 		baseURL:           parsedBase,
 		logger:            logger,
-		limiter:           NewRateLimiter(limiterOpts),
 		retryConfig:       retryConfig,
+		retryPolicies:     make(map[string]RetryConfig, len(opts.RetryPolicies)),
+	}
+	for name, policy := range opts.RetryPolicies {
+		client.retryPolicies[name] = policy
+	}
+
+	shareKey := ""
+	if opts.ShareLimiters {
+		shareKey = parsedBase.String() + "|" + opts.ClientID
+	}
+	client.limiters, client.releaseLimiters = newLimiters(limiterOpts, opts.LimiterBuckets, shareKey)
+	if opts.Limiter != nil {
+		for bucket := range client.limiters {
+			if _, ok := opts.LimiterBuckets[bucket]; !ok {
+				client.limiters[bucket] = opts.Limiter
+			}
+		}
+	}
+	for bucket, limiter := range opts.BucketLimiters {
+		client.limiters[bucket] = limiter
+	}
+
+	if opts.MetadataCache != nil {
+		client.metadataCache = NewMetadataCache(*opts.MetadataCache)
+	} else if opts.ValidateRecords {
+		client.metadataCache = NewMetadataCache(DefaultMetadataCacheOptions())
+	}
+	client.validateRecords = opts.ValidateRecords
+
+	if opts.CircuitBreaker != nil {
+		breakerOpts := *opts.CircuitBreaker
+		onStateChange := breakerOpts.OnStateChange
+		breakerOpts.OnStateChange = func(from, to CircuitState) {
+			client.log(LoggerLevelWarn, "[client] Circuit breaker state changed: %s -> %s", from, to)
+			if onStateChange != nil {
+				onStateChange(from, to)
+			}
+		}
+		client.breaker = NewCircuitBreaker(breakerOpts)
+	}
+
+	client.idempotencyJournal = opts.IdempotencyJournal
+	if client.idempotencyJournal == nil {
+		client.idempotencyJournal = NewMemoryIdempotencyJournal(24 * time.Hour)
 	}
 
 	client.Object = newObjectService(client)
@@ -127,6 +210,18 @@ func NewClient(opts ClientOptions) (*Client, error) {
 	return client, nil
 }
 
+// Close releases the limiters shared through ClientOptions.ShareLimiters; it is
+// required for such clients, as nothing else releases them. The client must not be
+// used afterwards. Close is safe to call more than once.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		if c.releaseLimiters != nil {
+			c.releaseLimiters()
+		}
+	})
+	return nil
+}
+
 // Init primes the client by ensuring a valid token is available.
 func (c *Client) Init(ctx context.Context) error {
 	if err := c.ensureTokenValid(ctx); err != nil {
@@ -169,6 +264,11 @@ func (c *Client) TokenExpiresIn() (time.Duration, bool) {
 	return remaining, true
 }
 
+// MetadataCache returns the metadata cache, or nil when caching is disabled.
+func (c *Client) MetadataCache() *MetadataCache {
+	return c.metadataCache
+}
+
 // Namespace returns the namespace associated with the client.
 func (c *Client) Namespace() string {
 	return c.namespace
@@ -257,7 +357,7 @@ func (c *Client) refreshAccessTokenLocked(ctx context.Context) error {
 }
 
 func (c *Client) doJSON(ctx context.Context, method, path string, body any, auth bool, headers map[string]string) (*APIResponse, error) {
-	var reader io.Reader
+	var payload []byte
 
 	if body != nil {
 		buf := &bytes.Buffer{}
@@ -266,7 +366,7 @@ func (c *Client) doJSON(ctx context.Context, method, path string, body any, auth
 		if err := encoder.Encode(body); err != nil {
 			return nil, fmt.Errorf("failed to encode request body: %w", err)
 		}
-		reader = buf
+		payload = buf.Bytes()
 
 		if headers == nil {
 			headers = make(map[string]string)
@@ -281,14 +381,64 @@ func (c *Client) doJSON(ctx context.Context, method, path string, body any, auth
 		headers["Accept"] = "application/json"
 	}
 
+	op := operationFor(method, path)
+	limiter := c.limiterFor(op)
+	retryConfig := c.retryConfigFor(ctx, op)
+	journalKey := ""
+	if !op.Idempotent() {
+		// 非幂等请求在一次调用内只重试服务端明确拒绝、未处理的请求，带幂等键时也是如此：
+		// 平台未承诺按 Idempotency-Key 去重，超时等结果未知的失败重发可能重复执行，
+		// 因此直接返回，由调用方确认结果后决定是否重发
+		retryIf := retryConfig.RetryIf
+		retryConfig.RetryIf = func(err error) bool {
+			return isRejectedRequest(err) && (retryIf == nil || retryIf(err))
+		}
+
+		if key := idempotencyKeyFrom(ctx); key != "" {
+			journalKey = requestIdempotencyKey(key, method, path, payload)
+			entry, err := c.idempotencyJournal.Begin(ctx, journalKey, op)
+			if err != nil {
+				return nil, fmt.Errorf("failed to record idempotency key: %w", err)
+			}
+			if entry != nil {
+				if entry.State == IdempotencyCompleted && entry.Response != nil {
+					c.log(LoggerLevelDebug, "[client] Replaying recorded response: %s, key=%s", op, journalKey)
+					resp := *entry.Response
+					return &resp, nil
+				}
+				return nil, fmt.Errorf("%w: %s, key=%s", ErrIdempotencyPending, op, journalKey)
+			}
+			headers[HeaderIdempotencyKey] = journalKey
+		}
+	}
+
 	var resp *http.Response
 	var err error
+	// ambiguous reports whether the last attempt sent may have been processed
+	ambiguous := false
 
 	// Execute with retry logic
-	retryErr := Retry(ctx, c.retryConfig, func() error {
-		resp, err = c.doRequestRaw(ctx, method, path, reader, headers, auth)
+	retryErr := retry(ctx, retryConfig, op, func() error {
+		if err := limiter.Wait(ctx); err != nil {
+			return &limiterError{err: err}
+		}
+		var ticket CircuitTicket
+		if c.breaker != nil {
+			if ticket, err = c.breaker.Allow(); err != nil {
+				return err
+			}
+		}
+
+		var reader io.Reader
+		if payload != nil {
+			reader = bytes.NewReader(payload)
+		}
+		ambiguous = true
+		resp, err = c.send(ctx, method, path, reader, headers, auth)
 		if err != nil {
-			return &NetworkError{Operation: "http request", Err: err}
+			err = &NetworkError{Operation: "http request", Err: err}
+			c.recordOutcome(ticket, err)
+			return err
 		}
 
 		// Check for retryable HTTP status codes
@@ -299,13 +449,27 @@ func (c *Client) doJSON(ctx context.Context, method, path string, body any, auth
 			resp.StatusCode == http.StatusGatewayTimeout {
 			defer resp.Body.Close()
 			bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-			return newAPIError(resp.StatusCode, "", string(bodyBytes), method, path, nil)
+			apiErr := newAPIError(resp.StatusCode, "", string(bodyBytes), method, path, nil)
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+				apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			}
+			ambiguous = !isRejectedRequest(apiErr)
+			c.recordOutcome(ticket, apiErr)
+			if resp.StatusCode == http.StatusTooManyRequests {
+				c.adaptRate(limiter, true)
+			}
+			return apiErr
 		}
 
+		c.recordOutcome(ticket, nil)
 		return nil
 	})
 
 	if retryErr != nil {
+		// 只有最后发出的请求结果未知时才保留 pending 状态
+		if journalKey != "" && !ambiguous {
+			c.forgetIdempotencyKey(ctx, journalKey)
+		}
 		return nil, retryErr
 	}
 
@@ -313,6 +477,9 @@ func (c *Client) doJSON(ctx context.Context, method, path string, body any, auth
 
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
 		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if journalKey != "" {
+			c.forgetIdempotencyKey(ctx, journalKey)
+		}
 		return nil, newAPIError(resp.StatusCode, "", string(bodyBytes), method, path, nil)
 	}
 
@@ -321,6 +488,18 @@ func (c *Client) doJSON(ctx context.Context, method, path string, body any, auth
 		return nil, fmt.Errorf("failed to decode API response: %w", err)
 	}
 
+	throttled := isRateLimitCode(limiter, apiResp.Code)
+	c.adaptRate(limiter, throttled)
+
+	if journalKey != "" {
+		if throttled {
+			// 被限流的请求没有执行，不能作为结果重放
+			c.forgetIdempotencyKey(ctx, journalKey)
+		} else if err := c.idempotencyJournal.Complete(ctx, journalKey, &apiResp); err != nil {
+			c.log(LoggerLevelWarn, "[client] Failed to record response: %s, key=%s: %v", op, journalKey, err)
+		}
+	}
+
 	// Check for API-level errors
 	if apiResp.Code != "0" && apiResp.Code != "" {
 		requestID := resp.Header.Get("X-Request-Id")
@@ -338,6 +517,47 @@ func (c *Client) doJSON(ctx context.Context, method, path string, body any, auth
 	return &apiResp, nil
 }
 
+// isRejectedRequest reports whether the server refused a request without processing
+// it, so that sending it again cannot duplicate its effect.
+func isRejectedRequest(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == http.StatusServiceUnavailable
+}
+
+// recordOutcome reports the result of an attempt to the circuit breaker.
+func (c *Client) recordOutcome(ticket CircuitTicket, err error) {
+	if c.breaker != nil {
+		c.breaker.Record(ticket, err)
+	}
+}
+
+// adaptRate reports throttling or success to an adaptive rate limiter.
+func (c *Client) adaptRate(l Limiter, throttled bool) {
+	limiter, ok := l.(*RateLimiter)
+	if !ok {
+		return
+	}
+	if throttled {
+		if next, ok := limiter.throttled(); ok {
+			c.log(LoggerLevelWarn, "[client] Request throttled, rate lowered to %.2f req/s", next)
+		}
+		return
+	}
+	if next, ok := limiter.succeeded(); ok {
+		c.log(LoggerLevelDebug, "[client] Rate raised to %.2f req/s", next)
+	}
+}
+
+// forgetIdempotencyKey drops a journal entry whose request was not processed.
+func (c *Client) forgetIdempotencyKey(ctx context.Context, key string) {
+	if err := c.idempotencyJournal.Forget(ctx, key); err != nil {
+		c.log(LoggerLevelWarn, "[client] Failed to forget idempotency key %s: %v", key, err)
+	}
+}
+
 func (c *Client) doBinary(ctx context.Context, method, path string, body io.Reader, headers map[string]string, auth bool) ([]byte, http.Header, error) {
 	resp, err := c.doRequestRaw(ctx, method, path, body, headers, auth)
 	if err != nil {
@@ -358,7 +578,35 @@ func (c *Client) doBinary(ctx context.Context, method, path string, body io.Read
 	return data, resp.Header.Clone(), nil
 }
 
+// doRequestRaw waits for the rate limiter and the circuit breaker and sends a single
+// request. The breaker sees 429 and 5xx responses as failures; the body is left
+// unread for the caller.
 func (c *Client) doRequestRaw(ctx context.Context, method, path string, body io.Reader, headers map[string]string, auth bool) (*http.Response, error) {
+	if err := c.limiterFor(operationFor(method, path)).Wait(ctx); err != nil {
+		return nil, &limiterError{err: err}
+	}
+	var ticket CircuitTicket
+	if c.breaker != nil {
+		var err error
+		if ticket, err = c.breaker.Allow(); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.send(ctx, method, path, body, headers, auth)
+	if err != nil {
+		c.recordOutcome(ticket, &NetworkError{Operation: "http request", Err: err})
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		c.recordOutcome(ticket, newAPIError(resp.StatusCode, "", "", method, path, nil))
+	} else {
+		c.recordOutcome(ticket, nil)
+	}
+	return resp, nil
+}
+
+func (c *Client) send(ctx context.Context, method, path string, body io.Reader, headers map[string]string, auth bool) (*http.Response, error) {
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
@@ -388,7 +636,13 @@ func (c *Client) doRequestRaw(ctx context.Context, method, path string, body io.
 		req.Header.Set(k, v)
 	}
 
-	return c.httpClient.Do(req)
+	httpClient := c.httpClient
+	if ctx.Value(noClientTimeoutContextKey{}) != nil && httpClient.Timeout > 0 {
+		unbounded := *httpClient
+		unbounded.Timeout = 0
+		httpClient = &unbounded
+	}
+	return httpClient.Do(req)
 }
 
 func (c *Client) getAccessToken() string {
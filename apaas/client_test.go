@@ -0,0 +1,77 @@
+package apaas
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient starts a stand-in aPaaS server. Token requests are answered
+// automatically; every other request is passed to handler.
+func newTestClient(t *testing.T, opts ClientOptions, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/v1/appToken" {
+			writeTestJSON(w, map[string]any{
+				"code": "0",
+				"data": map[string]any{
+					"accessToken": "test-token",
+					"expireTime":  time.Now().Add(time.Hour).UnixMilli(),
+				},
+			})
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	opts.Namespace = "app_test"
+	opts.ClientID = "client-id"
+	opts.ClientSecret = "client-secret"
+	opts.BaseURL = server.URL
+	if opts.Logger == nil {
+		opts.Logger = &discardLogger{}
+	}
+
+	client, err := NewClient(opts)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func writeTestJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func decodeTestBody(t *testing.T, r *http.Request) map[string]any {
+	t.Helper()
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatalf("failed to read request body: %v", err)
+	}
+	body := map[string]any{}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Fatalf("failed to decode request body %q: %v", data, err)
+		}
+	}
+	return body
+}
+
+type discardLogger struct {
+	level LoggerLevel
+}
+
+func (l *discardLogger) Log(LoggerLevel, string, ...any) {}
+
+func (l *discardLogger) SetLevel(level LoggerLevel) { l.level = level }
+
+func (l *discardLogger) Level() LoggerLevel { return l.level }
@@ -0,0 +1,159 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultVersionField is the system field used to detect concurrent modifications.
+const DefaultVersionField = "_updatedAt"
+
+// ObjectConditionalUpdateParams updates a record only if its version was unchanged
+// when checked; see RecordIfUnchanged for why this is best-effort.
+type ObjectConditionalUpdateParams struct {
+	ObjectName      string
+	RecordID        string
+	Record          map[string]any
+	ExpectedVersion any    // 读取记录时得到的版本值
+	VersionField    string // 默认 _updatedAt
+}
+
+// ObjectReadModifyWriteParams runs a read-modify-write loop on a single record.
+type ObjectReadModifyWriteParams struct {
+	ObjectName   string
+	RecordID     string
+	Select       []string // Modify 需要读取的字段，版本字段会自动加入
+	VersionField string   // 默认 _updatedAt
+	MaxAttempts  int      // 冲突时的最大尝试次数，默认 3
+	// Modify returns the fields to update for the current record. Returning nil or an
+	// empty map leaves the record untouched.
+	Modify func(record map[string]any) (map[string]any, error)
+}
+
+// RecordIfUnchanged is a best-effort stale-write guard, not conflict detection. It
+// re-reads the version field of a record and sends the update only if it still
+// equals ExpectedVersion; otherwise it returns a *ConflictError.
+//
+// The record update endpoint accepts no precondition, so the PATCH itself is
+// unconditional: a write that lands between the version read and the PATCH is
+// overwritten and no error is reported. The helper only catches modifications
+// that were already visible before the update was sent; serialize writers of the
+// same record when lost updates are unacceptable.
+func (s *ObjectUpdateService) RecordIfUnchanged(ctx context.Context, params ObjectConditionalUpdateParams) (*APIResponse, error) {
+	versionField := params.VersionField
+	if versionField == "" {
+		versionField = DefaultVersionField
+	}
+	if params.ExpectedVersion == nil {
+		return nil, fmt.Errorf("expected version is required")
+	}
+	params.VersionField = versionField
+
+	resp, _, err := s.recordIfVersion(ctx, params, []string{versionField})
+	return resp, err
+}
+
+// recordIfVersion reads selectFields, which include the version field, and sends
+// the update if the version still equals params.ExpectedVersion. On a conflict it
+// returns the record it read, so that callers can retry without reading it again.
+func (s *ObjectUpdateService) recordIfVersion(ctx context.Context, params ObjectConditionalUpdateParams, selectFields []string) (*APIResponse, map[string]any, error) {
+	current, err := s.client.Object.Search.record(ctx, params.ObjectName, params.RecordID, selectFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	if actual := current[params.VersionField]; !jsonEqual(actual, params.ExpectedVersion) {
+		s.client.log(LoggerLevelWarn, "[object.update.recordIfUnchanged] Version conflict: %s.%s, expected=%v, actual=%v", params.ObjectName, params.RecordID, params.ExpectedVersion, actual)
+		return nil, current, &ConflictError{
+			ObjectName: params.ObjectName,
+			RecordID:   params.RecordID,
+			Expected:   params.ExpectedVersion,
+			Actual:     actual,
+		}
+	}
+
+	resp, err := s.Record(ctx, ObjectUpdateRecordParams{
+		ObjectName: params.ObjectName,
+		RecordID:   params.RecordID,
+		Record:     params.Record,
+	})
+	return resp, nil, err
+}
+
+// ReadModifyWrite reads a record, applies Modify and writes the result if the
+// version is unchanged, starting over with the newer record when it changed in
+// between. Every attempt reads the record once. After MaxAttempts conflicts the
+// last *ConflictError is returned. Like RecordIfUnchanged it is best-effort: a
+// write racing with the final update can still be lost.
+func (s *ObjectUpdateService) ReadModifyWrite(ctx context.Context, params ObjectReadModifyWriteParams) (*APIResponse, error) {
+	if params.Modify == nil {
+		return nil, fmt.Errorf("modify function is required")
+	}
+	versionField := params.VersionField
+	if versionField == "" {
+		versionField = DefaultVersionField
+	}
+	maxAttempts := params.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	selectFields := append([]string{versionField}, params.Select...)
+
+	record, err := s.client.Object.Search.record(ctx, params.ObjectName, params.RecordID, selectFields)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		patch, err := params.Modify(cloneMap(record))
+		if err != nil {
+			return nil, err
+		}
+		if len(patch) == 0 {
+			return nil, nil
+		}
+
+		// 检查版本时读取的记录即为下一次尝试的输入
+		resp, current, err := s.recordIfVersion(ctx, ObjectConditionalUpdateParams{
+			ObjectName:      params.ObjectName,
+			RecordID:        params.RecordID,
+			Record:          patch,
+			ExpectedVersion: record[versionField],
+			VersionField:    versionField,
+		}, selectFields)
+		if !errors.Is(err, ErrConflict) {
+			return resp, err
+		}
+
+		lastErr = err
+		record = current
+		s.client.log(LoggerLevelDebug, "[object.update.readModifyWrite] Attempt %d/%d conflicted: %s.%s", attempt, maxAttempts, params.ObjectName, params.RecordID)
+	}
+	return nil, lastErr
+}
+
+// record fetches a single record and returns its fields.
+func (s *ObjectSearchService) record(ctx context.Context, objectName, recordID string, selectFields []string) (map[string]any, error) {
+	resp, err := s.Record(ctx, ObjectSearchRecordParams{ObjectName: objectName, RecordID: recordID, Select: selectFields})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var data map[string]any
+	if err := resp.DecodeData(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode record: %w", err)
+	}
+	// 兼容 data.item 与 data 直接为记录两种结构
+	if item, ok := data["item"].(map[string]any); ok {
+		return item, nil
+	}
+	if data == nil {
+		return nil, fmt.Errorf("record %s not found in %s", recordID, objectName)
+	}
+	return data, nil
+}
@@ -0,0 +1,77 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestObjectUpdateReadModifyWrite(t *testing.T) {
+	// versions returned by successive reads; the check of the first attempt sees a concurrent write
+	versions := []string{"v1", "v2", "v2"}
+	reads := 0
+	var patched map[string]any
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		body := decodeTestBody(t, r)
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/records/r1"):
+			version := versions[reads]
+			reads++
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{
+				"item": map[string]any{"_id": "r1", "_updatedAt": version, "count": 1},
+			}})
+		case r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/records/r1"):
+			patched = body["record"].(map[string]any)
+			writeTestJSON(w, map[string]any{"code": "0"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	attempts := 0
+	_, err := client.Object.Update.ReadModifyWrite(context.Background(), ObjectReadModifyWriteParams{
+		ObjectName: "object_counter",
+		RecordID:   "r1",
+		Select:     []string{"count"},
+		Modify: func(record map[string]any) (map[string]any, error) {
+			attempts++
+			return map[string]any{"count": record["count"].(float64) + 1}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 2 || reads != 3 {
+		t.Errorf("attempts=%d reads=%d, want 2 and 3", attempts, reads)
+	}
+	if patched["count"] != float64(2) {
+		t.Errorf("unexpected patch: %v", patched)
+	}
+}
+
+func TestObjectUpdateRecordIfUnchanged_Conflict(t *testing.T) {
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			t.Error("conflicting record must not be updated")
+		}
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{
+			"item": map[string]any{"_id": "r1", "_updatedAt": 200},
+		}})
+	})
+
+	_, err := client.Object.Update.RecordIfUnchanged(context.Background(), ObjectConditionalUpdateParams{
+		ObjectName:      "object_counter",
+		RecordID:        "r1",
+		Record:          map[string]any{"count": 3},
+		ExpectedVersion: 100,
+	})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ConflictError, got %v", err)
+	}
+	if conflict.Actual != float64(200) {
+		t.Errorf("unexpected actual version: %v", conflict.Actual)
+	}
+}
@@ -25,39 +25,32 @@ type DepartmentBatchExchangeParams struct {
 
 // Exchange performs a single department ID exchange.
 func (s *DepartmentService) Exchange(ctx context.Context, params DepartmentExchangeParams) (map[string]any, error) {
-	var result map[string]any
-
-	err := s.client.limiter.Do(ctx, func() error {
-		if err := s.client.ensureTokenValid(ctx); err != nil {
-			return err
-		}
-
-		endpoint := "/api/integration/v2/feishu/getDepartments"
-		payload := map[string]any{
-			"department_id_type": params.DepartmentIDType,
-			"department_ids":     []string{params.DepartmentID},
-		}
+	if err := s.client.ensureTokenValid(ctx); err != nil {
+		return nil, err
+	}
 
-		s.client.log(LoggerLevelInfo, "[department.exchange] Exchanging department ID: %s", params.DepartmentID)
+	endpoint := "/api/integration/v2/feishu/getDepartments"
+	payload := map[string]any{
+		"department_id_type": params.DepartmentIDType,
+		"department_ids":     []string{params.DepartmentID},
+	}
 
-		resp, err := s.client.doJSON(ctx, http.MethodPost, endpoint, payload, true, nil)
-		if err != nil {
-			return err
-		}
+	s.client.log(LoggerLevelInfo, "[department.exchange] Exchanging department ID: %s", params.DepartmentID)
 
-		var data []map[string]any
-		if err := resp.DecodeData(&data); err != nil {
-			return fmt.Errorf("failed to decode department exchange response: %w", err)
-		}
-		if len(data) == 0 {
-			return fmt.Errorf("department exchange returned no data")
-		}
+	resp, err := s.client.doJSON(ctx, http.MethodPost, endpoint, payload, true, nil)
+	if err != nil {
+		return nil, err
+	}
 
-		result = data[0]
-		return nil
-	})
+	var data []map[string]any
+	if err := resp.DecodeData(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode department exchange response: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("department exchange returned no data")
+	}
 
-	return result, err
+	return data[0], nil
 }
 
 // BatchExchange exchanges department IDs in batches of 100.
@@ -80,33 +73,27 @@ func (s *DepartmentService) BatchExchange(ctx context.Context, params Department
 
 		s.client.log(LoggerLevelInfo, "[department.batchExchange] Processing chunk %d/%d: %d IDs", chunkIndex, (len(params.DepartmentIDs)+chunkSize-1)/chunkSize, len(chunk))
 
-		err := s.client.limiter.Do(ctx, func() error {
-			if err := s.client.ensureTokenValid(ctx); err != nil {
-				return err
-			}
-
-			endpoint := "/api/integration/v2/feishu/getDepartments"
-			payload := map[string]any{
-				"department_id_type": params.DepartmentIDType,
-				"department_ids":     chunk,
-			}
-
-			resp, err := s.client.doJSON(ctx, http.MethodPost, endpoint, payload, true, nil)
-			if err != nil {
-				return err
-			}
-
-			var data []map[string]any
-			if err := resp.DecodeData(&data); err != nil {
-				return fmt.Errorf("failed to decode department batch response: %w", err)
-			}
-
-			results = append(results, data...)
-			return nil
-		})
+		if err := s.client.ensureTokenValid(ctx); err != nil {
+			return nil, err
+		}
+
+		endpoint := "/api/integration/v2/feishu/getDepartments"
+		payload := map[string]any{
+			"department_id_type": params.DepartmentIDType,
+			"department_ids":     chunk,
+		}
+
+		resp, err := s.client.doJSON(ctx, http.MethodPost, endpoint, payload, true, nil)
 		if err != nil {
 			return nil, err
 		}
+
+		var data []map[string]any
+		if err := resp.DecodeData(&data); err != nil {
+			return nil, fmt.Errorf("failed to decode department batch response: %w", err)
+		}
+
+		results = append(results, data...)
 	}
 
 	return results, nil
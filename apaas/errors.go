@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // Common errors
@@ -18,6 +19,11 @@ var (
 	ErrServiceUnavailable = errors.New("service unavailable")
 	ErrTimeout            = errors.New("request timeout")
 	ErrCanceled           = errors.New("request canceled")
+	ErrMaxRecordsExceeded = errors.New("matched records exceed the configured maximum")
+	ErrConflict           = errors.New("record was modified concurrently")
+	ErrIdempotencyPending = errors.New("request with this idempotency key is in flight or has an unknown outcome")
+	ErrCircuitOpen        = errors.New("circuit breaker is open")
+	ErrFunctionFailed     = errors.New("cloud function failed")
 )
 
 // APIError represents an error from the aPaaS API with detailed context.
@@ -36,6 +42,8 @@ type APIError struct {
 	Method string
 	// Err is the underlying error, if any
 	Err error
+	// RetryAfter is the delay requested by a Retry-After header on 429 and 503 responses
+	RetryAfter time.Duration
 }
 
 // Error implements the error interface.
@@ -75,6 +83,90 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("validation error: field=%s, msg=%s", e.Field, e.Message)
 }
 
+// RecordsValidationError reports client-side validation failures of records before a write.
+type RecordsValidationError struct {
+	ObjectName string
+	Results    []RecordValidationResult
+}
+
+// Error implements the error interface.
+func (e *RecordsValidationError) Error() string {
+	count := 0
+	for _, result := range e.Results {
+		count += len(result.Errors)
+	}
+	if len(e.Results) == 0 {
+		return fmt.Sprintf("validation failed for object %s", e.ObjectName)
+	}
+	first := e.Results[0]
+	return fmt.Sprintf("validation failed for %d record(s) of %s with %d error(s), first: record %d: %v",
+		len(e.Results), e.ObjectName, count, first.Index, first.Errors[0])
+}
+
+// Unwrap returns the individual validation errors.
+func (e *RecordsValidationError) Unwrap() []error {
+	errs := make([]error, 0)
+	for _, result := range e.Results {
+		for _, err := range result.Errors {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// ConflictError reports that the version of a record changed after the caller read
+// it. It is returned by a best-effort check before an unconditional update, so its
+// absence does not prove there was no concurrent write; see
+// ObjectUpdateService.RecordIfUnchanged.
+type ConflictError struct {
+	ObjectName string
+	RecordID   string
+	Expected   any
+	Actual     any
+}
+
+// Error implements the error interface.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("conflict updating %s.%s: expected version %v, got %v", e.ObjectName, e.RecordID, e.Expected, e.Actual)
+}
+
+// Unwrap returns ErrConflict so that errors.Is(err, ErrConflict) matches.
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// FlowError reports a flow instance that failed or was canceled.
+type FlowError struct {
+	InstanceID string
+	Status     FlowStatus
+	Code       string
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *FlowError) Error() string {
+	return fmt.Sprintf("flow instance %s %s: code=%s, msg=%s", e.InstanceID, e.Status, e.Code, e.Message)
+}
+
+// FunctionError reports an error returned by a cloud function itself, i.e. a code
+// listed in FunctionOptions.ErrorCodes, as opposed to platform errors such as
+// authentication, throttling or transport failures.
+type FunctionError struct {
+	Name    string
+	Code    string
+	Message string
+}
+
+// Error implements the error interface.
+func (e *FunctionError) Error() string {
+	return fmt.Sprintf("cloud function %s failed: code=%s, msg=%s", e.Name, e.Code, e.Message)
+}
+
+// Unwrap returns ErrFunctionFailed so that errors.Is(err, ErrFunctionFailed) matches.
+func (e *FunctionError) Unwrap() error {
+	return ErrFunctionFailed
+}
+
 // NetworkError represents network-level errors.
 type NetworkError struct {
 	Operation string
@@ -0,0 +1,149 @@
+package apaas
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FilterOperator is a comparison operator of a records_query criterion.
+type FilterOperator string
+
+// Criterion operators supported by records_query.
+const (
+	FilterEquals     FilterOperator = "equals"
+	FilterNotEquals  FilterOperator = "notEquals"
+	FilterGreater    FilterOperator = "gt"
+	FilterGreaterEq  FilterOperator = "gte"
+	FilterLess       FilterOperator = "lt"
+	FilterLessEq     FilterOperator = "lte"
+	FilterContains   FilterOperator = "contain"
+	FilterHasAnyOf   FilterOperator = "hasAnyOf"
+	FilterHasNoneOf  FilterOperator = "hasNoneOf"
+	FilterIsEmpty    FilterOperator = "isEmpty"
+	FilterIsNotEmpty FilterOperator = "isNotEmpty"
+)
+
+// FilterCondition compares a field with a constant value.
+type FilterCondition struct {
+	Field    string
+	Operator FilterOperator
+	Value    any
+}
+
+// RecordFilter builds the criterion passed as "filter" to records_query.
+type RecordFilter struct {
+	Conditions []FilterCondition
+	// Logic combines conditions by their 1-based position, e.g. "1 and (2 or 3)".
+	// Empty means all conditions must match.
+	Logic string
+}
+
+// NewRecordFilter returns a filter matching records that satisfy all conditions.
+func NewRecordFilter(conditions ...FilterCondition) *RecordFilter {
+	return &RecordFilter{Conditions: conditions}
+}
+
+// Where is shorthand for a FilterCondition.
+func Where(field string, operator FilterOperator, value any) FilterCondition {
+	return FilterCondition{Field: field, Operator: operator, Value: value}
+}
+
+var logicIndexPattern = regexp.MustCompile(`\d+`)
+
+// And returns a new filter matching records that satisfy both filters.
+func (f *RecordFilter) And(other *RecordFilter) *RecordFilter {
+	return f.combine(other, "and")
+}
+
+// Or returns a new filter matching records that satisfy either filter.
+func (f *RecordFilter) Or(other *RecordFilter) *RecordFilter {
+	return f.combine(other, "or")
+}
+
+func (f *RecordFilter) combine(other *RecordFilter, op string) *RecordFilter {
+	if f.isEmpty() {
+		return other.clone()
+	}
+	if other.isEmpty() {
+		return f.clone()
+	}
+
+	offset := len(f.Conditions)
+	right := logicIndexPattern.ReplaceAllStringFunc(other.logic(), func(index string) string {
+		n, _ := strconv.Atoi(index)
+		return strconv.Itoa(n + offset)
+	})
+
+	conditions := make([]FilterCondition, 0, len(f.Conditions)+len(other.Conditions))
+	conditions = append(conditions, f.Conditions...)
+	conditions = append(conditions, other.Conditions...)
+
+	return &RecordFilter{
+		Conditions: conditions,
+		Logic:      fmt.Sprintf("(%s) %s (%s)", f.logic(), op, right),
+	}
+}
+
+func (f *RecordFilter) isEmpty() bool {
+	return f == nil || len(f.Conditions) == 0
+}
+
+func (f *RecordFilter) clone() *RecordFilter {
+	if f == nil {
+		return nil
+	}
+	return &RecordFilter{Conditions: append([]FilterCondition(nil), f.Conditions...), Logic: f.Logic}
+}
+
+// logic returns the explicit logic expression or an AND over all conditions.
+func (f *RecordFilter) logic() string {
+	if f.Logic != "" {
+		return f.Logic
+	}
+	indexes := make([]string, 0, len(f.Conditions))
+	for i := range f.Conditions {
+		indexes = append(indexes, strconv.Itoa(i+1))
+	}
+	return strings.Join(indexes, " and ")
+}
+
+// build renders the criterion format expected by records_query for the given object.
+func (f *RecordFilter) build(objectName string) map[string]any {
+	conditions := make([]map[string]any, 0, len(f.Conditions))
+	for i, condition := range f.Conditions {
+		left := map[string]any{
+			"fieldPath": []map[string]any{{"fieldApiName": condition.Field, "objectApiName": objectName}},
+		}
+
+		entry := map[string]any{
+			"index":    strconv.Itoa(i + 1),
+			"left":     map[string]any{"type": "metadataVariable", "settings": mustJSON(left)},
+			"operator": string(condition.Operator),
+		}
+		if condition.Operator != FilterIsEmpty && condition.Operator != FilterIsNotEmpty {
+			entry["right"] = map[string]any{"type": "constant", "settings": mustJSON(map[string]any{"data": condition.Value})}
+		}
+		conditions = append(conditions, entry)
+	}
+
+	return map[string]any{
+		"conditions": conditions,
+		"logic":      f.logic(),
+	}
+}
+
+// applyFilter sets the filter of a records_query payload. A raw "filter" already
+// present in data is only allowed when no RecordFilter is given.
+func applyFilter(data map[string]any, objectName string, filter *RecordFilter) (map[string]any, error) {
+	payload := cloneMap(data)
+	if filter.isEmpty() {
+		return payload, nil
+	}
+	if _, ok := payload["filter"]; ok {
+		return nil, fmt.Errorf("data.filter and Filter cannot be used together")
+	}
+	payload["filter"] = filter.build(objectName)
+	return payload, nil
+}
@@ -0,0 +1,71 @@
+package apaas
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRecordFilter_Logic(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *RecordFilter
+		want   string
+	}{
+		{"implicit and", NewRecordFilter(Where("a", FilterEquals, 1), Where("b", FilterEquals, 2)), "1 and 2"},
+		{"and", NewRecordFilter(Where("a", FilterEquals, 1)).And(NewRecordFilter(Where("b", FilterGreater, 2), Where("c", FilterLess, 3))), "(1) and (2 and 3)"},
+		{"or with explicit logic", (&RecordFilter{Conditions: []FilterCondition{Where("a", FilterEquals, 1), Where("b", FilterEquals, 2)}, Logic: "1 or 2"}).Or(NewRecordFilter(Where("c", FilterIsEmpty, nil))), "(1 or 2) or (3)"},
+		{"and with empty", NewRecordFilter(Where("a", FilterEquals, 1)).And(nil), "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.logic(); got != tt.want {
+				t.Errorf("logic() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordFilter_Build(t *testing.T) {
+	filter := NewRecordFilter(Where("status", FilterEquals, "open"), Where("owner", FilterIsEmpty, nil))
+	built := filter.build("object_store")
+
+	conditions := built["conditions"].([]map[string]any)
+	if len(conditions) != 2 || built["logic"] != "1 and 2" {
+		t.Fatalf("unexpected filter: %+v", built)
+	}
+
+	left := conditions[0]["left"].(map[string]any)
+	var settings struct {
+		FieldPath []struct {
+			FieldAPIName  string `json:"fieldApiName"`
+			ObjectAPIName string `json:"objectApiName"`
+		} `json:"fieldPath"`
+	}
+	if err := json.Unmarshal([]byte(left["settings"].(string)), &settings); err != nil {
+		t.Fatalf("invalid left settings: %v", err)
+	}
+	if settings.FieldPath[0].FieldAPIName != "status" || settings.FieldPath[0].ObjectAPIName != "object_store" {
+		t.Errorf("unexpected field path: %+v", settings)
+	}
+
+	right := conditions[0]["right"].(map[string]any)
+	if right["settings"] != `{"data":"open"}` {
+		t.Errorf("unexpected right settings: %v", right["settings"])
+	}
+	if _, ok := conditions[1]["right"]; ok {
+		t.Error("isEmpty condition must not carry a right operand")
+	}
+}
+
+func TestApplyFilter_Conflict(t *testing.T) {
+	_, err := applyFilter(map[string]any{"filter": map[string]any{}}, "object_store", NewRecordFilter(Where("a", FilterEquals, 1)))
+	if err == nil {
+		t.Error("expected conflict error, got nil")
+	}
+
+	payload, err := applyFilter(map[string]any{"filter": "raw"}, "object_store", nil)
+	if err != nil || payload["filter"] != "raw" {
+		t.Errorf("expected raw filter to be kept, got %v, %v", payload, err)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !unix
+
+package apaas
+
+import (
+	"os"
+)
+
+// tryLockFile creates a lock file next to file, as flock is not available.
+func tryLockFile(file *os.File) (func(), error) {
+	return tryCreateLockFile(file.Name()+".lock", staleLockAge)
+}
+
+// processAlive reports whether a process with the given PID exists. Where
+// os.FindProcess cannot tell, the process is assumed alive and only the age of the
+// lock file counts.
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = process.Release()
+	return true
+}
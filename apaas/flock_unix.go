@@ -0,0 +1,29 @@
+//go:build unix
+
+package apaas
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLockFile takes a non-blocking flock on file.
+func tryLockFile(file *os.File) (func(), error) {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return nil, errLockBusy
+	}
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+	}, nil
+}
+
+// processAlive reports whether a process with the given PID exists.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,258 @@
+package apaas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// FlowStatus is the normalized state of a flow instance.
+type FlowStatus string
+
+// Flow instance states.
+const (
+	FlowStatusRunning   FlowStatus = "running"
+	FlowStatusWaiting   FlowStatus = "waiting" // 等待审批或人工任务
+	FlowStatusCompleted FlowStatus = "completed"
+	FlowStatusFailed    FlowStatus = "failed"
+	FlowStatusCanceled  FlowStatus = "canceled"
+	FlowStatusUnknown   FlowStatus = "unknown"
+)
+
+// IsTerminal reports whether the instance will not change state anymore.
+func (s FlowStatus) IsTerminal() bool {
+	return s == FlowStatusCompleted || s == FlowStatusFailed || s == FlowStatusCanceled
+}
+
+// normalizeFlowStatus maps the status spellings returned by v1 and v2 flows.
+func normalizeFlowStatus(status string) FlowStatus {
+	switch strings.ToLower(status) {
+	case "running", "in_progress", "processing", "executing":
+		return FlowStatusRunning
+	case "waiting", "pending", "pending_approval", "approving", "paused":
+		return FlowStatusWaiting
+	case "completed", "complete", "success", "succeeded", "finished", "done":
+		return FlowStatusCompleted
+	case "failed", "fail", "error":
+		return FlowStatusFailed
+	case "canceled", "cancelled", "terminated", "revoked":
+		return FlowStatusCanceled
+	}
+	return FlowStatusUnknown
+}
+
+// FlowExecution is the typed result of a flow execution or instance query.
+type FlowExecution struct {
+	InstanceID   string         `json:"instanceId"`
+	Status       FlowStatus     `json:"status"`
+	RawStatus    string         `json:"rawStatus,omitempty"`
+	Outputs      map[string]any `json:"outputs,omitempty"`
+	ErrorCode    string         `json:"errorCode,omitempty"`
+	ErrorMessage string         `json:"errorMessage,omitempty"`
+}
+
+// Err returns a *FlowError when the instance failed or was canceled.
+func (e *FlowExecution) Err() error {
+	if e.Status != FlowStatusFailed && e.Status != FlowStatusCanceled {
+		return nil
+	}
+	return &FlowError{InstanceID: e.InstanceID, Status: e.Status, Code: e.ErrorCode, Message: e.ErrorMessage}
+}
+
+// UnmarshalJSON accepts the field names used by v1 and v2 responses.
+func (e *FlowExecution) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ExecutionID   json.RawMessage `json:"executionId"`
+		InstanceID    json.RawMessage `json:"instanceId"`
+		InstanceIDAlt json.RawMessage `json:"instance_id"`
+		Status        string          `json:"status"`
+		Data          map[string]any  `json:"data"`
+		Output        map[string]any  `json:"output"`
+		OutParams     map[string]any  `json:"outParams"`
+		ErrorCode     json.RawMessage `json:"errorCode"`
+		ErrorMsg      string          `json:"errorMsg"`
+		ErrorMessage  string          `json:"errorMessage"`
+		ErrorMsgAlt   string          `json:"error_msg"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*e = FlowExecution{RawStatus: raw.Status, Status: normalizeFlowStatus(raw.Status)}
+	for _, id := range []json.RawMessage{raw.InstanceID, raw.InstanceIDAlt, raw.ExecutionID} {
+		if value := rawScalar(id); value != "" {
+			e.InstanceID = value
+			break
+		}
+	}
+	for _, outputs := range []map[string]any{raw.Output, raw.OutParams, raw.Data} {
+		if outputs != nil {
+			e.Outputs = outputs
+			break
+		}
+	}
+	e.ErrorCode = rawScalar(raw.ErrorCode)
+	for _, message := range []string{raw.ErrorMessage, raw.ErrorMsg, raw.ErrorMsgAlt} {
+		if message != "" {
+			e.ErrorMessage = message
+			break
+		}
+	}
+	if e.ErrorCode == "0" {
+		e.ErrorCode = ""
+	}
+	return nil
+}
+
+// rawScalar renders a JSON string or number without quotes.
+func rawScalar(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// ExecuteTyped runs a v1 flow and decodes the execution result.
+func (s *AutomationV1Service) ExecuteTyped(ctx context.Context, params AutomationV1ExecuteParams) (*FlowExecution, error) {
+	resp, err := s.Execute(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFlowExecution(resp)
+}
+
+// ExecuteTyped runs a v2 flow and decodes the execution result.
+func (s *AutomationV2Service) ExecuteTyped(ctx context.Context, params AutomationV2ExecuteParams) (*FlowExecution, error) {
+	resp, err := s.Execute(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFlowExecution(resp)
+}
+
+// Instance queries the current state of a v2 flow instance.
+func (s *AutomationV2Service) Instance(ctx context.Context, instanceID string) (*FlowExecution, error) {
+	if err := s.client.ensureTokenValid(ctx); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf(
+		"/v2/namespaces/%s/flows/instances/%s",
+		url.PathEscape(s.client.namespace),
+		url.PathEscape(instanceID),
+	)
+
+	s.client.log(LoggerLevelDebug, "[automation.v2.instance] Querying flow instance: %s", instanceID)
+
+	resp, err := s.client.doJSON(ctx, http.MethodGet, endpoint, nil, true, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	execution, err := decodeFlowExecution(resp)
+	if err != nil {
+		return nil, err
+	}
+	if execution.InstanceID == "" {
+		execution.InstanceID = instanceID
+	}
+
+	s.client.log(LoggerLevelDebug, "[automation.v2.instance] Flow instance queried: %s, status=%s", instanceID, execution.RawStatus)
+	return execution, nil
+}
+
+// FlowWaitOptions controls polling in Wait.
+type FlowWaitOptions struct {
+	Interval    time.Duration // 首次轮询间隔，默认 1 秒
+	MaxInterval time.Duration // 指数退避上限，默认 15 秒
+	// StopOnWaiting returns as soon as the instance waits for approval or a manual task.
+	StopOnWaiting bool
+	// MaxUnknownPolls stops waiting with an error after this many consecutive polls
+	// return a status the SDK does not recognize, 5 by default.
+	MaxUnknownPolls int
+}
+
+// Wait polls a v2 flow instance until it reaches a terminal state or ctx is done.
+// A failed or canceled instance is returned together with its *FlowError. An
+// unrecognized status reported MaxUnknownPolls times in a row ends the wait with an
+// error, so that a new platform status cannot make Wait poll forever.
+func (s *AutomationV2Service) Wait(ctx context.Context, instanceID string, opts FlowWaitOptions) (*FlowExecution, error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	maxInterval := opts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = 15 * time.Second
+	}
+	maxUnknown := opts.MaxUnknownPolls
+	if maxUnknown <= 0 {
+		maxUnknown = 5
+	}
+
+	unknown := 0
+	for {
+		execution, err := s.Instance(ctx, instanceID)
+		if err != nil {
+			return nil, err
+		}
+		if execution.Status == FlowStatusUnknown {
+			if unknown++; unknown >= maxUnknown {
+				return execution, fmt.Errorf("flow instance %s reported unrecognized status %q %d times in a row", instanceID, execution.RawStatus, unknown)
+			}
+		} else {
+			unknown = 0
+		}
+		if execution.Status.IsTerminal() {
+			s.client.log(LoggerLevelInfo, "[automation.v2.wait] Flow instance finished: %s, status=%s", instanceID, execution.Status)
+			return execution, execution.Err()
+		}
+		if opts.StopOnWaiting && execution.Status == FlowStatusWaiting {
+			return execution, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return execution, ctx.Err()
+		case <-timer.C:
+		}
+		interval = min(interval*2, maxInterval)
+	}
+}
+
+// Resubmit re-executes a v2 flow for a failed instance, passing its ID as
+// pre_instance_id with is_resubmit set.
+func (s *AutomationV2Service) Resubmit(ctx context.Context, previous *FlowExecution, params AutomationV2ExecuteParams) (*FlowExecution, error) {
+	if previous == nil || previous.InstanceID == "" {
+		return nil, fmt.Errorf("previous instance is required")
+	}
+	if previous.Status != FlowStatusFailed {
+		return nil, fmt.Errorf("instance %s is %s, only failed instances can be resubmitted", previous.InstanceID, previous.Status)
+	}
+
+	resubmit := true
+	params.IsResubmit = &resubmit
+	params.PreInstanceID = previous.InstanceID
+	return s.ExecuteTyped(ctx, params)
+}
+
+func decodeFlowExecution(resp *APIResponse) (*FlowExecution, error) {
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+	var execution FlowExecution
+	if err := resp.DecodeData(&execution); err != nil {
+		return nil, fmt.Errorf("failed to decode flow execution: %w", err)
+	}
+	return &execution, nil
+}
@@ -0,0 +1,95 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlowExecution_Unmarshal(t *testing.T) {
+	resp := &APIResponse{Code: "0", Data: []byte(`{"executionId":1234567890123,"status":"FAILED","errorCode":"k_ec_001","errorMsg":"boom","data":{"a":1}}`)}
+	execution, err := decodeFlowExecution(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if execution.InstanceID != "1234567890123" || execution.Status != FlowStatusFailed || execution.Outputs["a"] != float64(1) {
+		t.Errorf("unexpected execution: %+v", execution)
+	}
+
+	var flowErr *FlowError
+	if !errors.As(execution.Err(), &flowErr) || flowErr.Code != "k_ec_001" || flowErr.Message != "boom" {
+		t.Errorf("unexpected error: %v", execution.Err())
+	}
+}
+
+func TestAutomationV2_WaitAndResubmit(t *testing.T) {
+	statuses := []string{"running", "pending_approval", "failed"}
+	polls := 0
+	var resubmitted map[string]any
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/flows/instances/i1"):
+			status := statuses[min(polls, len(statuses)-1)]
+			polls++
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"status": status, "errorMsg": "rejected"}})
+		case strings.HasSuffix(r.URL.Path, "/flows/flow_a/execute"):
+			resubmitted = decodeTestBody(t, r)
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"executionId": "i2", "status": "completed"}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	automation := client.Automation.V2
+
+	waiting, err := automation.Wait(context.Background(), "i1", FlowWaitOptions{Interval: time.Millisecond, StopOnWaiting: true})
+	if err != nil || waiting.Status != FlowStatusWaiting || polls != 2 {
+		t.Fatalf("StopOnWaiting: execution=%+v polls=%d err=%v", waiting, polls, err)
+	}
+
+	failed, err := automation.Wait(context.Background(), "i1", FlowWaitOptions{Interval: time.Millisecond})
+	var flowErr *FlowError
+	if !errors.As(err, &flowErr) || failed.InstanceID != "i1" || failed.Status != FlowStatusFailed {
+		t.Fatalf("expected failed instance, got %+v, %v", failed, err)
+	}
+
+	result, err := automation.Resubmit(context.Background(), failed, AutomationV2ExecuteParams{FlowAPIName: "flow_a"})
+	if err != nil || result.InstanceID != "i2" || result.Status != FlowStatusCompleted {
+		t.Fatalf("unexpected resubmit result: %+v, %v", result, err)
+	}
+	if resubmitted["is_resubmit"] != true || resubmitted["pre_instance_id"] != "i1" {
+		t.Errorf("unexpected resubmit payload: %v", resubmitted)
+	}
+
+	if _, err := automation.Resubmit(context.Background(), result, AutomationV2ExecuteParams{FlowAPIName: "flow_a"}); err == nil {
+		t.Error("expected error resubmitting a completed instance")
+	}
+}
+
+func TestAutomationV2_WaitTimeout(t *testing.T) {
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"status": "running"}})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	execution, err := client.Automation.V2.Wait(ctx, "i1", FlowWaitOptions{Interval: 5 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) || execution == nil || execution.Status != FlowStatusRunning {
+		t.Errorf("expected deadline exceeded with last state, got %+v, %v", execution, err)
+	}
+}
+
+func TestAutomationV2_WaitStopsOnUnknownStatus(t *testing.T) {
+	polls := 0
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"status": "brand_new_status"}})
+	})
+
+	execution, err := client.Automation.V2.Wait(context.Background(), "i1", FlowWaitOptions{Interval: time.Millisecond, MaxUnknownPolls: 3})
+	if err == nil || polls != 3 || execution.Status != FlowStatusUnknown || execution.RawStatus != "brand_new_status" {
+		t.Fatalf("expected Wait to give up after 3 unknown statuses, got %+v, polls=%d, err=%v", execution, polls, err)
+	}
+}
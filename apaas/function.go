@@ -20,6 +20,10 @@ type FunctionInvokeParams struct {
 
 // Invoke executes a cloud function.
 func (s *FunctionService) Invoke(ctx context.Context, params FunctionInvokeParams) (*APIResponse, error) {
+	return s.invoke(ctx, params.Name, params.Params)
+}
+
+func (s *FunctionService) invoke(ctx context.Context, name string, params any) (*APIResponse, error) {
 	if err := s.client.ensureTokenValid(ctx); err != nil {
 		return nil, err
 	}
@@ -27,20 +31,20 @@ func (s *FunctionService) Invoke(ctx context.Context, params FunctionInvokeParam
 	endpoint := fmt.Sprintf(
 		"/api/cloudfunction/v1/namespaces/%s/invoke/%s",
 		url.PathEscape(s.client.namespace),
-		url.PathEscape(params.Name),
+		url.PathEscape(name),
 	)
 
 	payload := map[string]any{
-		"params": params.Params,
+		"params": params,
 	}
 
-	s.client.log(LoggerLevelInfo, "[function.invoke] Invoking cloud function: %s", params.Name)
+	s.client.log(LoggerLevelInfo, "[function.invoke] Invoking cloud function: %s", name)
 
 	resp, err := s.client.doJSON(ctx, http.MethodPost, endpoint, payload, true, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	s.client.log(LoggerLevelDebug, "[function.invoke] Cloud function invoked: %s, code=%s", params.Name, resp.Code)
+	s.client.log(LoggerLevelDebug, "[function.invoke] Cloud function invoked: %s, code=%s", name, resp.Code)
 	return resp, nil
 }
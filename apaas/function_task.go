@@ -0,0 +1,208 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FunctionTaskStatus is the status of an asynchronous cloud function invocation.
+type FunctionTaskStatus string
+
+// Function task statuses.
+const (
+	FunctionTaskRunning   FunctionTaskStatus = "running"
+	FunctionTaskSucceeded FunctionTaskStatus = "succeeded"
+	FunctionTaskFailed    FunctionTaskStatus = "failed"
+	FunctionTaskCanceled  FunctionTaskStatus = "canceled"
+)
+
+// IsTerminal reports whether the task has finished.
+func (s FunctionTaskStatus) IsTerminal() bool {
+	return s != FunctionTaskRunning
+}
+
+// FunctionAsyncOptions configures an asynchronous invocation.
+type FunctionAsyncOptions struct {
+	// Timeout bounds the invocation, including retries; zero means it runs until it
+	// completes or the task is canceled. The HTTP client timeout does not apply.
+	Timeout time.Duration
+}
+
+// FunctionTask is the handle of a cloud function invoked with InvokeAsync. It tracks
+// a goroutine holding a synchronous invoke request, not a task on the platform: the
+// task ends with the process and cannot be resumed elsewhere.
+type FunctionTask struct {
+	ID        string
+	Name      string
+	StartedAt time.Time
+
+	client *Client
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu         sync.Mutex
+	status     FunctionTaskStatus
+	resp       *APIResponse
+	err        error
+	finishedAt time.Time
+	canceled   bool
+}
+
+type noClientTimeoutContextKey struct{}
+
+// withoutClientTimeout lets requests made with ctx outlive the HTTP client timeout;
+// they are bounded by ctx alone.
+func withoutClientTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noClientTimeoutContextKey{}, true)
+}
+
+// InvokeAsync starts a cloud function in the background and returns its task handle
+// at once. The invocation keeps the values of ctx, such as WithIdempotencyKey and
+// WithPriority, but not its cancellation: use FunctionTask.Cancel to stop it.
+//
+// This is not an asynchronous platform endpoint. The OpenAPI invoke endpoint is
+// synchronous, so a goroutine holds the request open until the function returns;
+// only the HTTP client timeout is lifted.
+func (s *FunctionService) InvokeAsync(ctx context.Context, params FunctionInvokeParams, opts FunctionAsyncOptions) (*FunctionTask, error) {
+	return s.invokeAsync(ctx, params.Name, params.Params, opts)
+}
+
+func (s *FunctionService) invokeAsync(ctx context.Context, name string, params any, opts FunctionAsyncOptions) (*FunctionTask, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("function name is required")
+	}
+	if err := s.client.ensureTokenValid(ctx); err != nil {
+		return nil, err
+	}
+
+	taskCtx := withoutClientTimeout(context.WithoutCancel(ctx))
+	var cancel context.CancelFunc
+	if opts.Timeout > 0 {
+		taskCtx, cancel = context.WithTimeout(taskCtx, opts.Timeout)
+	} else {
+		taskCtx, cancel = context.WithCancel(taskCtx)
+	}
+
+	task := &FunctionTask{
+		ID:        NewIdempotencyKey(),
+		Name:      name,
+		StartedAt: time.Now(),
+		client:    s.client,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		status:    FunctionTaskRunning,
+	}
+
+	s.client.log(LoggerLevelInfo, "[function.invoke.async] Starting cloud function task: %s, task=%s", name, task.ID)
+
+	go func() {
+		defer cancel()
+		resp, err := s.invoke(taskCtx, name, params)
+		task.finish(resp, err)
+	}()
+	return task, nil
+}
+
+func (t *FunctionTask) finish(resp *APIResponse, err error) {
+	t.mu.Lock()
+	t.resp, t.err = resp, err
+	t.finishedAt = time.Now()
+	switch {
+	case t.canceled && errors.Is(err, context.Canceled):
+		// 只有请求确实因 Cancel 中断时才记为取消，已返回的结果保持不变
+		t.status = FunctionTaskCanceled
+		t.err = context.Canceled
+	case err != nil:
+		t.status = FunctionTaskFailed
+	case checkResponse(resp) != nil:
+		t.status = FunctionTaskFailed
+	default:
+		t.status = FunctionTaskSucceeded
+	}
+	status := t.status
+	t.mu.Unlock()
+	close(t.done)
+
+	t.client.log(LoggerLevelInfo, "[function.invoke.async] Cloud function task finished: %s, task=%s, status=%s, elapsed=%s",
+		t.Name, t.ID, status, t.finishedAt.Sub(t.StartedAt))
+}
+
+// Status returns the current status without blocking.
+func (t *FunctionTask) Status() FunctionTaskStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+// Done returns a channel closed when the task finishes.
+func (t *FunctionTask) Done() <-chan struct{} {
+	return t.done
+}
+
+// Poll returns the status and, once the task has finished, its result. Like
+// FunctionService.Invoke, a response with a non-zero code is returned without error;
+// the status is FunctionTaskFailed in that case.
+func (t *FunctionTask) Poll() (FunctionTaskStatus, *APIResponse, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status, t.resp, t.err
+}
+
+// Wait blocks until the task finishes or ctx is done. Giving up waiting does not
+// cancel the task.
+func (t *FunctionTask) Wait(ctx context.Context) (*APIResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-t.done:
+	}
+	_, resp, err := t.Poll()
+	return resp, err
+}
+
+// Cancel aborts the request of a running task, whose status becomes
+// FunctionTaskCanceled. The function may still complete on the platform if it had
+// already started. When the response arrives before the request is aborted, the
+// task keeps that result. Canceling a finished task has no effect.
+func (t *FunctionTask) Cancel() {
+	t.mu.Lock()
+	if t.status.IsTerminal() {
+		t.mu.Unlock()
+		return
+	}
+	t.canceled = true
+	t.mu.Unlock()
+
+	t.client.log(LoggerLevelInfo, "[function.invoke.async] Canceling cloud function task: %s, task=%s", t.Name, t.ID)
+	t.cancel()
+	<-t.done
+}
+
+// InvokeAsync starts the function in the background; see FunctionService.InvokeAsync.
+// FunctionOptions.Timeout bounds the task. Use Wait to decode the result.
+func (f *TypedFunction[In, Out]) InvokeAsync(ctx context.Context, client *Client, input In) (*FunctionTask, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	return client.Function.invokeAsync(ctx, f.name, input, FunctionAsyncOptions{Timeout: f.opts.Timeout})
+}
+
+// Wait waits for a task started with InvokeAsync and decodes its result like Invoke.
+func (f *TypedFunction[In, Out]) Wait(ctx context.Context, task *FunctionTask) (Out, error) {
+	var out Out
+	if task == nil {
+		return out, fmt.Errorf("function task is required")
+	}
+	if task.Name != f.name {
+		return out, fmt.Errorf("task %s belongs to cloud function %s, not %s", task.ID, task.Name, f.name)
+	}
+	resp, err := task.Wait(ctx)
+	if err != nil {
+		return out, err
+	}
+	return f.decode(task.client, resp)
+}
@@ -0,0 +1,121 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFunctionTask_WaitOutlivesClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestClient(t, ClientOptions{HTTPClient: &http.Client{Timeout: 50 * time.Millisecond}}, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"updated": 3}})
+	})
+	update := DefineFunction[memberUpdateInput, memberUpdateOutput]("StoreMemberUpdate", FunctionOptions{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	task, err := update.InvokeAsync(ctx, client, memberUpdateInput{StoreID: "s1"})
+	cancel() // 任务不受发起调用的 ctx 取消影响
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if status, resp, err := task.Poll(); status != FunctionTaskRunning || resp != nil || err != nil {
+		t.Fatalf("expected running task, got %s, %v, %v", status, resp, err)
+	}
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer waitCancel()
+	if _, err := task.Wait(waitCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected Wait to give up, got %v", err)
+	}
+
+	close(release)
+	out, err := update.Wait(context.Background(), task)
+	if err != nil || out.Updated != 3 || task.Status() != FunctionTaskSucceeded {
+		t.Fatalf("unexpected result: %+v, %v, status=%s", out, err, task.Status())
+	}
+}
+
+func TestFunctionTask_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+
+	task, err := client.Function.InvokeAsync(context.Background(), FunctionInvokeParams{Name: "LongRunning"}, FunctionAsyncOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-started
+	task.Cancel()
+
+	select {
+	case <-task.Done():
+	default:
+		t.Fatal("expected Cancel to wait for the task")
+	}
+	if status, _, err := task.Poll(); status != FunctionTaskCanceled || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled task, got %s, %v", status, err)
+	}
+	task.Cancel() // 已结束的任务再次取消无影响
+}
+
+func TestFunctionTask_FailureAndTimeout(t *testing.T) {
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/cloudfunction/v1/namespaces/app_test/invoke/Slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(200 * time.Millisecond):
+			}
+			return
+		}
+		writeTestJSON(w, map[string]any{"code": "k_cf_001", "msg": "store not found"})
+	})
+
+	update := DefineFunction[memberUpdateInput, memberUpdateOutput]("StoreMemberUpdate", FunctionOptions{ErrorCodes: []string{"k_cf_*"}})
+	task, err := update.InvokeAsync(context.Background(), client, memberUpdateInput{StoreID: "s1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var fnErr *FunctionError
+	if _, err := update.Wait(context.Background(), task); !errors.As(err, &fnErr) || task.Status() != FunctionTaskFailed {
+		t.Fatalf("expected function error, got %v, status=%s", err, task.Status())
+	}
+	if _, err := DefineFunction[struct{}, any]("Other", FunctionOptions{}).Wait(context.Background(), task); err == nil {
+		t.Error("expected error waiting for another function's task")
+	}
+
+	task, err = client.Function.InvokeAsync(context.Background(), FunctionInvokeParams{Name: "Slow"}, FunctionAsyncOptions{Timeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := task.Wait(context.Background()); err == nil || task.Status() != FunctionTaskFailed {
+		t.Fatalf("expected timed out task, got %v, status=%s", err, task.Status())
+	}
+}
+
+func TestFunctionTask_CancelAfterResponseKeepsResult(t *testing.T) {
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {})
+	task := &FunctionTask{
+		ID:       "task_1",
+		Name:     "StoreMemberUpdate",
+		client:   client,
+		done:     make(chan struct{}),
+		status:   FunctionTaskRunning,
+		canceled: true, // Cancel 在请求返回后、结果记录前被调用
+	}
+
+	resp := &APIResponse{Code: "0"}
+	task.finish(resp, nil)
+	if status, got, err := task.Poll(); status != FunctionTaskSucceeded || got != resp || err != nil {
+		t.Fatalf("expected the successful result to be kept, got %s, %v, %v", status, got, err)
+	}
+}
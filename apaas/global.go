@@ -40,7 +40,14 @@ func newGlobalService(client *Client) *GlobalService {
 }
 
 // Detail retrieves global option details.
+// The response is served from the metadata cache when it is enabled.
 func (s *GlobalOptionsService) Detail(ctx context.Context, apiName string) (*APIResponse, error) {
+	return s.client.metadataCache.fetch(ctx, globalOptionCacheKey(apiName), func(ctx context.Context) (*APIResponse, error) {
+		return s.detail(ctx, apiName)
+	})
+}
+
+func (s *GlobalOptionsService) detail(ctx context.Context, apiName string) (*APIResponse, error) {
 	if err := s.client.ensureTokenValid(ctx); err != nil {
 		return nil, err
 	}
@@ -94,50 +101,39 @@ func (s *GlobalOptionsService) List(ctx context.Context, limit, offset int, filt
 
 // ListWithIterator retrieves all global options automatically.
 func (s *GlobalOptionsService) ListWithIterator(ctx context.Context, limit int, filter map[string]any) (*RecordsIteratorResult, error) {
-	if limit <= 0 {
-		limit = 100
-	}
-
-	results := &RecordsIteratorResult{
-		Items: make([]map[string]any, 0),
+	paginator := s.listIterator(ctx, "global.options.listWithIterator", filter, PaginatorOptions{PageSize: limit})
+	items, err := paginator.Collect()
+	if err != nil {
+		return nil, err
 	}
+	return &RecordsIteratorResult{Total: paginator.Total(), Items: items}, nil
+}
 
-	offset := 0
+// ListIterator lazily pages through all global options.
+func (s *GlobalOptionsService) ListIterator(ctx context.Context, filter map[string]any, opts PaginatorOptions) *Paginator[map[string]any] {
+	return s.listIterator(ctx, "global.options.listIterator", filter, opts)
+}
 
-	for {
-		resp, err := s.List(ctx, limit, offset, filter)
+func (s *GlobalOptionsService) listIterator(ctx context.Context, operation string, filter map[string]any, opts PaginatorOptions) *Paginator[map[string]any] {
+	fetch := func(ctx context.Context, req PageRequest) (*Page, error) {
+		resp, err := s.List(ctx, req.Limit, req.Offset, filter)
 		if err != nil {
 			return nil, err
 		}
-
-		var page struct {
-			Items []map[string]any `json:"items"`
-			Total int              `json:"total"`
-		}
-		if err := resp.DecodeData(&page); err != nil {
-			return nil, fmt.Errorf("failed to decode global options list: %w", err)
-		}
-
-		if results.Total == 0 && page.Total > 0 {
-			results.Total = page.Total
-		}
-		if len(page.Items) > 0 {
-			results.Items = append(results.Items, page.Items...)
-		}
-
-		s.client.log(LoggerLevelInfo, "[global.options.listWithIterator] Page completed: items=%d, offset=%d", len(page.Items), offset)
-
-		offset += limit
-		if len(results.Items) >= results.Total || len(page.Items) == 0 {
-			break
-		}
+		return decodePage(resp)
 	}
-
-	return results, nil
+	return newPaginator[map[string]any](ctx, s.client, operation, PaginationOffset, fetch, opts)
 }
 
 // Detail retrieves global variable details.
+// The response is served from the metadata cache when it is enabled.
 func (s *GlobalVariablesService) Detail(ctx context.Context, apiName string) (*APIResponse, error) {
+	return s.client.metadataCache.fetch(ctx, globalVariableCacheKey(apiName), func(ctx context.Context) (*APIResponse, error) {
+		return s.detail(ctx, apiName)
+	})
+}
+
+func (s *GlobalVariablesService) detail(ctx context.Context, apiName string) (*APIResponse, error) {
 	if err := s.client.ensureTokenValid(ctx); err != nil {
 		return nil, err
 	}
@@ -191,44 +187,26 @@ func (s *GlobalVariablesService) List(ctx context.Context, limit, offset int, fi
 
 // ListWithIterator retrieves all global variables automatically.
 func (s *GlobalVariablesService) ListWithIterator(ctx context.Context, limit int, filter map[string]any) (*RecordsIteratorResult, error) {
-	if limit <= 0 {
-		limit = 100
-	}
-
-	results := &RecordsIteratorResult{
-		Items: make([]map[string]any, 0),
+	paginator := s.listIterator(ctx, "global.variables.listWithIterator", filter, PaginatorOptions{PageSize: limit})
+	items, err := paginator.Collect()
+	if err != nil {
+		return nil, err
 	}
+	return &RecordsIteratorResult{Total: paginator.Total(), Items: items}, nil
+}
 
-	offset := 0
+// ListIterator lazily pages through all global variables.
+func (s *GlobalVariablesService) ListIterator(ctx context.Context, filter map[string]any, opts PaginatorOptions) *Paginator[map[string]any] {
+	return s.listIterator(ctx, "global.variables.listIterator", filter, opts)
+}
 
-	for {
-		resp, err := s.List(ctx, limit, offset, filter)
+func (s *GlobalVariablesService) listIterator(ctx context.Context, operation string, filter map[string]any, opts PaginatorOptions) *Paginator[map[string]any] {
+	fetch := func(ctx context.Context, req PageRequest) (*Page, error) {
+		resp, err := s.List(ctx, req.Limit, req.Offset, filter)
 		if err != nil {
 			return nil, err
 		}
-
-		var page struct {
-			Items []map[string]any `json:"items"`
-			Total int              `json:"total"`
-		}
-		if err := resp.DecodeData(&page); err != nil {
-			return nil, fmt.Errorf("failed to decode global variables list: %w", err)
-		}
-
-		if results.Total == 0 && page.Total > 0 {
-			results.Total = page.Total
-		}
-		if len(page.Items) > 0 {
-			results.Items = append(results.Items, page.Items...)
-		}
-
-		s.client.log(LoggerLevelInfo, "[global.variables.listWithIterator] Page completed: items=%d, offset=%d", len(page.Items), offset)
-
-		offset += limit
-		if len(results.Items) >= results.Total || len(page.Items) == 0 {
-			break
-		}
+		return decodePage(resp)
 	}
-
-	return results, nil
+	return newPaginator[map[string]any](ctx, s.client, operation, PaginationOffset, fetch, opts)
 }
@@ -0,0 +1,232 @@
+package apaas
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// HeaderIdempotencyKey carries the key of requests sent with WithIdempotencyKey. The
+// OpenAPI is not documented to honor it; deduplication relies on the client's journal.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey attaches a caller-chosen key to a logical operation, such as
+// creating an order record or running a flow for it. Non-idempotent requests sent
+// with the key are recorded in the client's IdempotencyJournal: repeating the
+// operation with the same key and the same request returns the recorded response
+// instead of sending it again, also after a restart when the journal is persistent.
+//
+// The key does not enable more retries: like every non-idempotent request, they are
+// retried within a call only when the server rejected them unprocessed (429, 503),
+// since the platform does not promise to deduplicate requests by Idempotency-Key and
+// resending after a timeout could run the operation twice. When the last request
+// sent failed with an unknown outcome, such as a timeout or another 5xx, the key
+// stays pending and later calls return ErrIdempotencyPending until the caller checks
+// the outcome and calls IdempotencyJournal.Forget. Otherwise a failed key is forgotten.
+//
+// A logical operation that sends several requests, e.g. RecordsWithIterator, derives
+// one key per request from the key and the request body.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+func idempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// NewIdempotencyKey returns a random key suitable for WithIdempotencyKey.
+func NewIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("apaas: failed to generate idempotency key: %v", err))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// requestIdempotencyKey derives the key of a single request from the operation key.
+func requestIdempotencyKey(key, method, path string, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(method + " " + path + "\n"))
+	sum.Write(body)
+	return key + "-" + hex.EncodeToString(sum.Sum(nil))[:16]
+}
+
+// IdempotencyState is the state of a journal entry.
+type IdempotencyState string
+
+// Journal entry states.
+const (
+	IdempotencyPending   IdempotencyState = "pending"
+	IdempotencyCompleted IdempotencyState = "completed"
+)
+
+// IdempotencyEntry records a request sent with an idempotency key.
+type IdempotencyEntry struct {
+	Key         string           `json:"key"`
+	Operation   Operation        `json:"operation"`
+	State       IdempotencyState `json:"state"`
+	Response    *APIResponse     `json:"response,omitempty"`
+	StartedAt   time.Time        `json:"startedAt"`
+	CompletedAt time.Time        `json:"completedAt,omitempty"`
+}
+
+// IdempotencyJournal records requests sent with an idempotency key.
+//
+// An entry stays pending when the outcome of its request is unknown, e.g. after a
+// timeout or a crash. Requests with a pending key fail with ErrIdempotencyPending
+// until the entry is forgotten, so that the operation runs at most once.
+type IdempotencyJournal interface {
+	// Begin creates a pending entry for key. When an entry already exists it is
+	// returned unchanged and no entry is created.
+	Begin(ctx context.Context, key string, op Operation) (*IdempotencyEntry, error)
+	// Complete stores the response of key.
+	Complete(ctx context.Context, key string, resp *APIResponse) error
+	// Forget removes key, allowing the request to be sent again.
+	Forget(ctx context.Context, key string) error
+}
+
+// MemoryIdempotencyJournal keeps entries in memory for a fixed time.
+type MemoryIdempotencyJournal struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]IdempotencyEntry
+	now     func() time.Time
+}
+
+// NewMemoryIdempotencyJournal returns a journal dropping entries after ttl.
+// A ttl <= 0 keeps entries forever.
+func NewMemoryIdempotencyJournal(ttl time.Duration) *MemoryIdempotencyJournal {
+	return &MemoryIdempotencyJournal{ttl: ttl, entries: make(map[string]IdempotencyEntry), now: time.Now}
+}
+
+// Begin implements IdempotencyJournal.
+func (j *MemoryIdempotencyJournal) Begin(ctx context.Context, key string, op Operation) (*IdempotencyEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := j.now()
+	if entry, ok := j.entries[key]; ok {
+		if j.ttl <= 0 || now.Sub(entry.StartedAt) < j.ttl {
+			return &entry, nil
+		}
+	}
+	j.entries[key] = IdempotencyEntry{Key: key, Operation: op, State: IdempotencyPending, StartedAt: now}
+	return nil, nil
+}
+
+// Complete implements IdempotencyJournal.
+func (j *MemoryIdempotencyJournal) Complete(ctx context.Context, key string, resp *APIResponse) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry := j.entries[key]
+	entry.Key = key
+	entry.State = IdempotencyCompleted
+	entry.Response = resp
+	entry.CompletedAt = j.now()
+	if entry.StartedAt.IsZero() {
+		entry.StartedAt = entry.CompletedAt
+	}
+	j.entries[key] = entry
+	return nil
+}
+
+// Forget implements IdempotencyJournal.
+func (j *MemoryIdempotencyJournal) Forget(ctx context.Context, key string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	delete(j.entries, key)
+	return nil
+}
+
+// FileIdempotencyJournal stores each entry as a JSON file in Dir, so that completed
+// and pending requests survive a restart. Entries are never expired; remove old
+// files from Dir as needed.
+type FileIdempotencyJournal struct {
+	Dir string
+}
+
+// Begin implements IdempotencyJournal. The pending entry is created exclusively, so
+// processes sharing Dir do not send the same request concurrently.
+func (j FileIdempotencyJournal) Begin(ctx context.Context, key string, op Operation) (*IdempotencyEntry, error) {
+	entry := IdempotencyEntry{Key: key, Operation: op, State: IdempotencyPending, StartedAt: time.Now()}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(j.path(key), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return j.load(key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return nil, file.Close()
+}
+
+// Complete implements IdempotencyJournal. The file is replaced atomically.
+func (j FileIdempotencyJournal) Complete(ctx context.Context, key string, resp *APIResponse) error {
+	entry := IdempotencyEntry{Key: key, State: IdempotencyCompleted, Response: resp, CompletedAt: time.Now()}
+	if previous, err := j.load(key); err == nil && previous != nil {
+		entry.Operation = previous.Operation
+		entry.StartedAt = previous.StartedAt
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmp := j.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.path(key))
+}
+
+// Forget implements IdempotencyJournal.
+func (j FileIdempotencyJournal) Forget(ctx context.Context, key string) error {
+	err := os.Remove(j.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (j FileIdempotencyJournal) load(key string) (*IdempotencyEntry, error) {
+	data, err := os.ReadFile(j.path(key))
+	if err != nil {
+		return nil, err
+	}
+	var entry IdempotencyEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode idempotency entry %s: %w", key, err)
+	}
+	return &entry, nil
+}
+
+func (j FileIdempotencyJournal) path(key string) string {
+	return filepath.Join(j.Dir, url.PathEscape(key)+".idempotency.json")
+}
+
+// IdempotencyJournal returns the journal of requests sent with an idempotency key.
+// Forget a pending key there once the outcome of its request has been checked.
+func (c *Client) IdempotencyJournal() IdempotencyJournal {
+	return c.idempotencyJournal
+}
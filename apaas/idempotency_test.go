@@ -0,0 +1,246 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func fastRetryConfig() *RetryConfig {
+	return &RetryConfig{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+}
+
+// flakyCreateHandler fails the first failures create requests with status and
+// records the idempotency key and body of every request.
+type flakyCreateHandler struct {
+	t        *testing.T
+	mu       sync.Mutex
+	status   int
+	failures int
+	keys     []string
+	bodies   []map[string]any
+}
+
+func (h *flakyCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.keys = append(h.keys, r.Header.Get(HeaderIdempotencyKey))
+	h.bodies = append(h.bodies, decodeTestBody(h.t, r))
+	if len(h.keys) <= h.failures {
+		w.WriteHeader(h.status)
+		return
+	}
+	writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"_id": len(h.keys)}})
+}
+
+func (h *flakyCreateHandler) calls() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.keys)
+}
+
+func TestCreateWithoutKeyIsNotRetriedAfterServerError(t *testing.T) {
+	handler := &flakyCreateHandler{t: t, status: http.StatusBadGateway, failures: 1}
+	client := newTestClient(t, ClientOptions{RetryConfig: fastRetryConfig()}, handler.ServeHTTP)
+
+	_, err := client.Object.Create.Record(context.Background(), ObjectCreateRecordParams{
+		ObjectName: "order",
+		Record:     map[string]any{"name": "A"},
+	})
+	if StatusCode(err) != http.StatusBadGateway {
+		t.Fatalf("expected 502 error, got %v", err)
+	}
+	if handler.calls() != 1 {
+		t.Fatalf("expected a single attempt, got %d", handler.calls())
+	}
+	if handler.keys[0] != "" {
+		t.Fatalf("expected no idempotency key header without a key, got %q", handler.keys[0])
+	}
+}
+
+func TestCreateWithoutKeyRetriesRejectedRequest(t *testing.T) {
+	handler := &flakyCreateHandler{t: t, status: http.StatusTooManyRequests, failures: 1}
+	client := newTestClient(t, ClientOptions{RetryConfig: fastRetryConfig()}, handler.ServeHTTP)
+
+	if _, err := client.Object.Create.Record(context.Background(), ObjectCreateRecordParams{
+		ObjectName: "order",
+		Record:     map[string]any{"name": "A"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if handler.calls() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", handler.calls())
+	}
+}
+
+func TestCreateWithKeyRetriesAndReplays(t *testing.T) {
+	handler := &flakyCreateHandler{t: t, status: http.StatusTooManyRequests, failures: 1}
+	client := newTestClient(t, ClientOptions{RetryConfig: fastRetryConfig()}, handler.ServeHTTP)
+
+	ctx := WithIdempotencyKey(context.Background(), "order-42")
+	params := ObjectCreateRecordParams{ObjectName: "order", Record: map[string]any{"name": "A"}}
+
+	first, err := client.Object.Create.Record(ctx, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if handler.calls() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", handler.calls())
+	}
+	if handler.keys[0] == "" || handler.keys[0] != handler.keys[1] {
+		t.Fatalf("expected the same key on both attempts: %v", handler.keys)
+	}
+	// 重试时请求体必须完整重发
+	for i, body := range handler.bodies {
+		record, _ := body["record"].(map[string]any)
+		if record["name"] != "A" {
+			t.Fatalf("attempt %d sent body %v", i, body)
+		}
+	}
+
+	replayed, err := client.Object.Create.Record(ctx, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if handler.calls() != 2 {
+		t.Fatalf("expected replay without request, got %d calls", handler.calls())
+	}
+	if string(replayed.Data) != string(first.Data) {
+		t.Fatalf("expected replayed response %s, got %s", first.Data, replayed.Data)
+	}
+
+	params.Record = map[string]any{"name": "B"}
+	if _, err := client.Object.Create.Record(ctx, params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if handler.calls() != 3 {
+		t.Fatalf("expected a different request to be sent, got %d calls", handler.calls())
+	}
+}
+
+func TestCreateWithKeyUnknownOutcomeIsPending(t *testing.T) {
+	handler := &flakyCreateHandler{t: t, status: http.StatusBadGateway, failures: 100}
+	client := newTestClient(t, ClientOptions{RetryConfig: fastRetryConfig()}, handler.ServeHTTP)
+
+	ctx := WithIdempotencyKey(context.Background(), "order-42")
+	params := ObjectCreateRecordParams{ObjectName: "order", Record: map[string]any{"name": "A"}}
+
+	if _, err := client.Object.Create.Record(ctx, params); StatusCode(err) != http.StatusBadGateway {
+		t.Fatalf("expected 502 error, got %v", err)
+	}
+	calls := handler.calls()
+	if calls != 1 {
+		t.Fatalf("expected an ambiguous failure not to be retried within the call, got %d attempts", calls)
+	}
+
+	_, err := client.Object.Create.Record(ctx, params)
+	if !errors.Is(err, ErrIdempotencyPending) {
+		t.Fatalf("expected ErrIdempotencyPending, got %v", err)
+	}
+	if handler.calls() != calls {
+		t.Fatalf("expected no request for a pending key")
+	}
+}
+
+func TestCreateWithKeyRejectedThenUnsentIsForgotten(t *testing.T) {
+	handler := &flakyCreateHandler{t: t, status: http.StatusServiceUnavailable, failures: 1}
+	limiter := &failAfterLimiter{allowed: 1, err: errors.New("limiter store unavailable")}
+	client := newTestClient(t, ClientOptions{
+		RetryConfig:    fastRetryConfig(),
+		BucketLimiters: map[string]Limiter{LimiterBucketRecordsWrite: limiter},
+	}, handler.ServeHTTP)
+
+	ctx := WithIdempotencyKey(context.Background(), "order-44")
+	params := ObjectCreateRecordParams{ObjectName: "order", Record: map[string]any{"name": "A"}}
+
+	// 第一次被 503 拒绝，重试在限流器处失败，没有结果未知的请求
+	if _, err := client.Object.Create.Record(ctx, params); !errors.Is(err, limiter.err) || handler.calls() != 1 {
+		t.Fatalf("expected the limiter error after one rejected attempt, got %v, calls=%d", err, handler.calls())
+	}
+
+	limiter.allowed = 100
+	resp, err := client.Object.Create.Record(ctx, params)
+	if err != nil || resp.Code != "0" || handler.calls() != 2 {
+		t.Fatalf("expected the key to be forgotten and the request sent again, got %+v, %v, calls=%d", resp, err, handler.calls())
+	}
+}
+
+// failAfterLimiter allows the first allowed waits and then fails with err.
+type failAfterLimiter struct {
+	allowed int
+	err     error
+}
+
+func (l *failAfterLimiter) Wait(ctx context.Context) error {
+	if l.allowed <= 0 {
+		return l.err
+	}
+	l.allowed--
+	return nil
+}
+
+func TestCreateWithKeyThrottledIsNotRecorded(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		if calls++; calls == 1 {
+			writeTestJSON(w, map[string]any{"code": "99991400", "msg": "request trigger frequency limit"})
+			return
+		}
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"_id": 1}})
+	})
+
+	ctx := WithIdempotencyKey(context.Background(), "order-43")
+	params := ObjectCreateRecordParams{ObjectName: "order", Record: map[string]any{"name": "A"}}
+
+	if resp, err := client.Object.Create.Record(ctx, params); err != nil || resp.Code != "99991400" {
+		t.Fatalf("expected a throttled response, got %+v, %v", resp, err)
+	}
+	resp, err := client.Object.Create.Record(ctx, params)
+	if err != nil || resp.Code != "0" || calls != 2 {
+		t.Fatalf("expected the throttled request to be sent again, got %+v, %v, calls=%d", resp, err, calls)
+	}
+}
+
+func TestFileIdempotencyJournalSurvivesRestart(t *testing.T) {
+	journal := FileIdempotencyJournal{Dir: t.TempDir()}
+	handler := &flakyCreateHandler{t: t}
+	ctx := WithIdempotencyKey(context.Background(), "flow-run-1")
+	params := AutomationV2ExecuteParams{FlowAPIName: "approve", Params: map[string]any{"amount": 1}}
+
+	for restart := 0; restart < 2; restart++ {
+		client := newTestClient(t, ClientOptions{IdempotencyJournal: journal}, handler.ServeHTTP)
+		if _, err := client.Automation.V2.Execute(ctx, params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if handler.calls() != 1 {
+		t.Fatalf("expected the flow to run once, got %d", handler.calls())
+	}
+}
+
+func TestOperationFor(t *testing.T) {
+	cases := []struct {
+		method, path string
+		want         Operation
+	}{
+		{http.MethodPost, "/v1/data/namespaces/app/objects/order/records_query", OpObjectSearchRecords},
+		{http.MethodPost, "/v1/data/namespaces/app/objects/order/records", OpObjectCreateRecord},
+		{http.MethodPost, "/v1/data/namespaces/app/objects/order/records/1", OpObjectSearchRecord},
+		{http.MethodPatch, "/v1/data/namespaces/app/objects/order/records_batch", OpObjectUpdateRecords},
+		{http.MethodPost, "/v2/namespaces/app/flows/approve/execute", OpAutomationV2Execute},
+		{http.MethodGet, "/v2/namespaces/app/flows/instances/1", OpAutomationV2Instance},
+		{http.MethodGet, "/somewhere/else", OpUnknown},
+	}
+	for _, tc := range cases {
+		if got := operationFor(tc.method, tc.path); got != tc.want {
+			t.Errorf("operationFor(%s %s) = %s, want %s", tc.method, tc.path, got, tc.want)
+		}
+	}
+	if OpObjectCreateRecords.Idempotent() || !OpObjectUpdateRecords.Idempotent() {
+		t.Fatalf("unexpected idempotency classification")
+	}
+}
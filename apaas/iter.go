@@ -0,0 +1,51 @@
+//go:build go1.23
+
+package apaas
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns a range-over-func iterator over the remaining items. Pages are fetched
+// lazily; an error is yielded once with the zero item and ends the iteration.
+func (p *Paginator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for p.Next() {
+			if !yield(p.Item(), nil) {
+				return
+			}
+		}
+		if err := p.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// All iterates over all matching records.
+//
+//	for record, err := range client.Object.Search.All(ctx, params) { ... }
+func (s *ObjectSearchService) All(ctx context.Context, params ObjectRecordsIteratorParams) iter.Seq2[map[string]any, error] {
+	return s.RecordsIterator(ctx, params, PaginatorOptions{}).All()
+}
+
+// All iterates over all objects.
+func (s *ObjectService) All(ctx context.Context, filter *ObjectListFilter) iter.Seq2[Object, error] {
+	return PaginatorAs[Object](s.ListIterator(ctx, filter, PaginatorOptions{})).All()
+}
+
+// All iterates over all pages.
+func (s *PageService) All(ctx context.Context) iter.Seq2[map[string]any, error] {
+	return s.ListIterator(ctx, PaginatorOptions{}).All()
+}
+
+// All iterates over all global options.
+func (s *GlobalOptionsService) All(ctx context.Context, filter map[string]any) iter.Seq2[map[string]any, error] {
+	return s.ListIterator(ctx, filter, PaginatorOptions{}).All()
+}
+
+// All iterates over all global variables.
+func (s *GlobalVariablesService) All(ctx context.Context, filter map[string]any) iter.Seq2[map[string]any, error] {
+	return s.ListIterator(ctx, filter, PaginatorOptions{}).All()
+}
@@ -0,0 +1,76 @@
+//go:build go1.23
+
+package apaas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPaginatorAll_EarlyStopAndError(t *testing.T) {
+	var requests []PageRequest
+	p := NewPaginator[paginatorItem](context.Background(), PaginationOffset, offsetFetcher(10, true, &requests), PaginatorOptions{PageSize: 2})
+
+	seen := 0
+	for item, err := range p.All() {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if seen = item.N + 1; seen == 3 {
+			break
+		}
+	}
+	if seen != 3 || len(requests) != 2 {
+		t.Errorf("seen=%d pages=%d, want 3 and 2", seen, len(requests))
+	}
+
+	failing := NewPaginator[paginatorItem](context.Background(), PaginationToken, func(ctx context.Context, req PageRequest) (*Page, error) {
+		if req.PageToken == "" {
+			return &Page{Items: []json.RawMessage{[]byte(`{"n":0}`)}, NextPageToken: "next"}, nil
+		}
+		return nil, errors.New("boom")
+	}, PaginatorOptions{})
+
+	var errs []error
+	count := 0
+	for _, err := range failing.All() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		count++
+	}
+	if count != 1 || len(errs) != 1 || errs[0].Error() != "boom" {
+		t.Errorf("count=%d errs=%v", count, errs)
+	}
+}
+
+func TestObjectSearchAll(t *testing.T) {
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/records_query") {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		if decodeTestBody(t, r)["page_token"] == "" {
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{
+				"items": []any{map[string]any{"_id": "1"}}, "next_page_token": "t",
+			}})
+			return
+		}
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"items": []any{map[string]any{"_id": "2"}}}})
+	})
+
+	var ids []string
+	for record, err := range client.Object.Search.All(context.Background(), ObjectRecordsIteratorParams{ObjectName: "object_store"}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, recordID(record))
+	}
+	if strings.Join(ids, ",") != "1,2" {
+		t.Errorf("ids = %v", ids)
+	}
+}
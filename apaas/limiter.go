@@ -2,16 +2,57 @@ package apaas
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/time/rate"
 )
 
+// Limiter paces requests. The client waits on it before every attempt.
+type Limiter interface {
+	// Wait blocks until the next request may be sent or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// limiterError reports that a request was not sent because waiting on the limiter
+// failed, e.g. because ctx ended or a shared limiter store was unreachable.
+type limiterError struct {
+	err error
+}
+
+func (e *limiterError) Error() string {
+	return fmt.Sprintf("rate limiter: %v", e.err)
+}
+
+func (e *limiterError) Unwrap() error {
+	return e.err
+}
+
 // LimiterOptions configures the request rate limiter.
 type LimiterOptions struct {
 	RequestsPerInterval int
 	Interval            time.Duration
 	Burst               int
+
+	// Adaptive lowers the rate when the platform throttles requests and raises it
+	// again while requests succeed (additive increase, multiplicative decrease).
+	Adaptive bool
+	// MinRequestsPerInterval is the lowest adaptive rate, 1 by default.
+	MinRequestsPerInterval int
+	// DecreaseFactor multiplies the rate on throttling, 0.5 by default.
+	DecreaseFactor float64
+	// IncreaseStep is added to the requests per interval after every RecoveryInterval
+	// without throttling, until RequestsPerInterval is reached again. Default 1.
+	IncreaseStep     float64
+	RecoveryInterval time.Duration // 默认 5 秒
+	// RateLimitCodes are business codes that signal throttling in addition to HTTP 429.
+	RateLimitCodes []string
+
+	// ReservedTokens are burst tokens only PriorityHigh requests may use, so that they
+	// can be sent at once while lower priority work keeps the limiter busy.
+	ReservedTokens int
 }
 
 // DefaultLimiterOptions returns a conservative limiter aligned with the Node.js SDK defaults.
@@ -23,9 +64,30 @@ func DefaultLimiterOptions() LimiterOptions {
 	}
 }
 
+// defaultRateLimitCodes is the OpenAPI "request trigger frequency limit" code.
+var defaultRateLimitCodes = []string{"99991400"}
+
 // RateLimiter wraps golang.org/x/time/rate limiter.
 type RateLimiter struct {
 	limiter *rate.Limiter
+	opts    LimiterOptions
+	now     func() time.Time
+
+	mu           sync.Mutex
+	maxRate      rate.Limit
+	minRate      rate.Limit
+	lastDecrease time.Time
+	lastIncrease time.Time
+
+	// 按优先级排队等待的请求，由 dispatch 依次放行
+	queueMu     sync.Mutex
+	queue       []*limiterWaiter
+	dispatching bool
+}
+
+type limiterWaiter struct {
+	priority Priority
+	ready    chan struct{}
 }
 
 // NewRateLimiter constructs a rate limiter using the provided options.
@@ -39,21 +101,357 @@ func NewRateLimiter(opts LimiterOptions) *RateLimiter {
 	if opts.Burst <= 0 {
 		opts.Burst = DefaultLimiterOptions().Burst
 	}
+	if opts.MinRequestsPerInterval <= 0 {
+		opts.MinRequestsPerInterval = 1
+	}
+	if opts.DecreaseFactor <= 0 || opts.DecreaseFactor >= 1 {
+		opts.DecreaseFactor = 0.5
+	}
+	if opts.IncreaseStep <= 0 {
+		opts.IncreaseStep = 1
+	}
+	if opts.RecoveryInterval <= 0 {
+		opts.RecoveryInterval = 5 * time.Second
+	}
+	if opts.RateLimitCodes == nil {
+		opts.RateLimitCodes = defaultRateLimitCodes
+	}
+	if opts.ReservedTokens >= opts.Burst {
+		opts.ReservedTokens = opts.Burst - 1
+	}
 
 	limit := rate.Every(opts.Interval / time.Duration(opts.RequestsPerInterval))
 	return &RateLimiter{
 		limiter: rate.NewLimiter(limit, opts.Burst),
+		opts:    opts,
+		now:     time.Now,
+		maxRate: limit,
+		minRate: min(limit, rate.Every(opts.Interval/time.Duration(opts.MinRequestsPerInterval))),
 	}
 }
 
-// Do waits for the next available slot and executes the provided function.
-func (r *RateLimiter) Do(ctx context.Context, fn func() error) error {
+// Wait blocks until the next request may be sent. Waiting requests are released
+// highest priority first (see WithPriority), in arrival order within a priority.
+func (r *RateLimiter) Wait(ctx context.Context) error {
 	if r == nil || r.limiter == nil {
-		return fn()
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	priority := PriorityFrom(ctx)
+	r.queueMu.Lock()
+	if len(r.queue) == 0 && r.limiter.Tokens() >= r.tokensNeeded(priority) {
+		r.limiter.Allow()
+		r.queueMu.Unlock()
+		return nil
+	}
+	waiter := &limiterWaiter{priority: priority, ready: make(chan struct{})}
+	r.queue = append(r.queue, waiter)
+	if !r.dispatching {
+		r.dispatching = true
+		go r.dispatch()
+	}
+	r.queueMu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		r.queueMu.Lock()
+		defer r.queueMu.Unlock()
+		for i, w := range r.queue {
+			if w == waiter {
+				r.queue = append(r.queue[:i], r.queue[i+1:]...)
+				return ctx.Err()
+			}
+		}
+		return nil // 已经放行
+	}
+}
+
+// tokensNeeded is the number of available tokens a request of priority requires.
+func (r *RateLimiter) tokensNeeded(priority Priority) float64 {
+	if priority >= PriorityHigh {
+		return 1
+	}
+	return float64(1 + r.opts.ReservedTokens)
+}
+
+// dispatch releases queued requests as tokens become available until the queue is empty.
+func (r *RateLimiter) dispatch() {
+	for {
+		r.queueMu.Lock()
+		if len(r.queue) == 0 {
+			r.dispatching = false
+			r.queueMu.Unlock()
+			return
+		}
+
+		next := 0
+		for i, w := range r.queue {
+			if w.priority > r.queue[next].priority {
+				next = i
+			}
+		}
+		waiter := r.queue[next]
+
+		missing := r.tokensNeeded(waiter.priority) - r.limiter.Tokens()
+		if missing <= 0 {
+			r.limiter.Allow()
+			r.queue = append(r.queue[:next], r.queue[next+1:]...)
+			close(waiter.ready)
+			r.queueMu.Unlock()
+			continue
+		}
+		delay := max(time.Duration(missing/float64(r.limiter.Limit())*float64(time.Second)), 100*time.Microsecond)
+		r.queueMu.Unlock()
+
+		// 等待期间到达的更高优先级请求会在下一轮优先放行
+		time.Sleep(delay)
 	}
+}
 
-	if err := r.limiter.Wait(ctx); err != nil {
+// Do waits for the next available slot and executes the provided function.
+func (r *RateLimiter) Do(ctx context.Context, fn func() error) error {
+	if err := r.Wait(ctx); err != nil {
 		return err
 	}
 	return fn()
 }
+
+// Rate returns the current rate in requests per second.
+func (r *RateLimiter) Rate() float64 {
+	if r == nil || r.limiter == nil {
+		return 0
+	}
+	return float64(r.limiter.Limit())
+}
+
+// throttled lowers an adaptive rate. Throttling reported within one interval of the
+// previous decrease is ignored, so that a burst of 429s halves the rate only once.
+func (r *RateLimiter) throttled() (float64, bool) {
+	if r == nil || !r.opts.Adaptive {
+		return 0, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if now.Sub(r.lastDecrease) < r.opts.Interval {
+		return 0, false
+	}
+	r.lastDecrease = now
+	r.lastIncrease = now
+
+	current := r.limiter.Limit()
+	next := max(current*rate.Limit(r.opts.DecreaseFactor), r.minRate)
+	if next == current {
+		return 0, false
+	}
+	r.limiter.SetLimit(next)
+	return float64(next), true
+}
+
+// succeeded raises an adaptive rate by IncreaseStep once per RecoveryInterval.
+func (r *RateLimiter) succeeded() (float64, bool) {
+	if r == nil || !r.opts.Adaptive {
+		return 0, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.limiter.Limit()
+	now := r.now()
+	if current >= r.maxRate || now.Sub(r.lastIncrease) < r.opts.RecoveryInterval {
+		return 0, false
+	}
+	r.lastIncrease = now
+
+	step := rate.Limit(r.opts.IncreaseStep / r.opts.Interval.Seconds())
+	next := min(current+step, r.maxRate)
+	r.limiter.SetLimit(next)
+	return float64(next), true
+}
+
+// isRateLimitCode reports whether a business code signals throttling to limiter.
+func isRateLimitCode(limiter Limiter, code string) bool {
+	r, ok := limiter.(*RateLimiter)
+	if !ok {
+		return false
+	}
+	for _, c := range r.opts.RateLimitCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimiter returns the default limiter, which applies to requests outside the
+// named buckets, such as authentication. It is nil when ClientOptions.Limiter replaced it.
+func (c *Client) RateLimiter() *RateLimiter {
+	limiter, _ := c.limiters[LimiterBucketDefault].(*RateLimiter)
+	return limiter
+}
+
+// Limiter buckets group operations that share a platform quota.
+const (
+	LimiterBucketDefault      = "default"
+	LimiterBucketRecordsRead  = "records.read"
+	LimiterBucketRecordsWrite = "records.write"
+	LimiterBucketMetadata     = "metadata"
+	LimiterBucketFlow         = "flow"
+	LimiterBucketFunction     = "function"
+)
+
+// LimiterBucket returns the limiter bucket of the operation.
+func (op Operation) LimiterBucket() string {
+	switch {
+	case strings.HasPrefix(string(op), "object.search."):
+		return LimiterBucketRecordsRead
+	case strings.HasPrefix(string(op), "object.create."),
+		strings.HasPrefix(string(op), "object.update."),
+		strings.HasPrefix(string(op), "object.delete."):
+		return LimiterBucketRecordsWrite
+	case op == OpObjectList,
+		strings.HasPrefix(string(op), "object.metadata."),
+		strings.HasPrefix(string(op), "global."),
+		strings.HasPrefix(string(op), "page."):
+		return LimiterBucketMetadata
+	case strings.HasPrefix(string(op), "automation."):
+		return LimiterBucketFlow
+	case strings.HasPrefix(string(op), "function."):
+		return LimiterBucketFunction
+	}
+	return LimiterBucketDefault
+}
+
+// DefaultLimiterBuckets returns the quota the SDK gives each bucket that has no
+// options in ClientOptions.LimiterBuckets. They are conservative SDK defaults, not
+// published platform quotas; raise them if your tenant allows more.
+//
+//	records.read   5 次/秒，突发 20
+//	records.write  3 次/秒，突发 10
+//	metadata       2 次/秒，突发 5
+//	flow           2 次/秒，突发 5
+//	function       2 次/秒，突发 5
+func DefaultLimiterBuckets() map[string]LimiterOptions {
+	return map[string]LimiterOptions{
+		LimiterBucketRecordsRead:  {RequestsPerInterval: 5, Interval: time.Second, Burst: 20},
+		LimiterBucketRecordsWrite: {RequestsPerInterval: 3, Interval: time.Second, Burst: 10},
+		LimiterBucketMetadata:     {RequestsPerInterval: 2, Interval: time.Second, Burst: 5},
+		LimiterBucketFlow:         {RequestsPerInterval: 2, Interval: time.Second, Burst: 5},
+		LimiterBucketFunction:     {RequestsPerInterval: 2, Interval: time.Second, Burst: 5},
+	}
+}
+
+// bucketDefaults applies a default bucket quota to the client's limiter options. The
+// adaptive settings are kept, and the quota never exceeds the client's own rate or burst.
+func bucketDefaults(defaults, quota LimiterOptions) LimiterOptions {
+	opts := defaults
+	base := DefaultLimiterOptions()
+	if opts.RequestsPerInterval <= 0 {
+		opts.RequestsPerInterval = base.RequestsPerInterval
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = base.Interval
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = base.Burst
+	}
+
+	if float64(quota.RequestsPerInterval)/quota.Interval.Seconds() < float64(opts.RequestsPerInterval)/opts.Interval.Seconds() {
+		opts.RequestsPerInterval, opts.Interval = quota.RequestsPerInterval, quota.Interval
+	}
+	if quota.Burst < opts.Burst {
+		opts.Burst = quota.Burst
+	}
+	return opts
+}
+
+// sharedLimiters holds the limiters of clients created with ShareLimiters, keyed by
+// base URL, client ID and bucket. An entry is removed once every client using it
+// has been closed.
+var sharedLimiters = struct {
+	sync.Mutex
+	limiters map[string]*sharedLimiter
+}{limiters: make(map[string]*sharedLimiter)}
+
+type sharedLimiter struct {
+	limiter *RateLimiter
+	refs    int
+}
+
+// newLimiters builds the limiter of every bucket: buckets without options in
+// buckets get their DefaultLimiterBuckets quota. The returned func releases the
+// shared limiters taken by the client.
+func newLimiters(defaults LimiterOptions, buckets map[string]LimiterOptions, shareKey string) (map[string]Limiter, func()) {
+	all := make(map[string]LimiterOptions, len(buckets)+1)
+	for bucket, quota := range DefaultLimiterBuckets() {
+		all[bucket] = bucketDefaults(defaults, quota)
+	}
+	for bucket, opts := range buckets {
+		all[bucket] = opts
+	}
+	all[LimiterBucketDefault] = defaults
+
+	limiters := make(map[string]Limiter, len(all))
+	if shareKey == "" {
+		for bucket, opts := range all {
+			limiters[bucket] = NewRateLimiter(opts)
+		}
+		return limiters, func() {}
+	}
+
+	sharedLimiters.Lock()
+	defer sharedLimiters.Unlock()
+	keys := make([]string, 0, len(all))
+	for bucket, opts := range all {
+		key := shareKey + "|" + bucket
+		entry, ok := sharedLimiters.limiters[key]
+		if !ok {
+			entry = &sharedLimiter{limiter: NewRateLimiter(opts)}
+			sharedLimiters.limiters[key] = entry
+		}
+		entry.refs++
+		keys = append(keys, key)
+		limiters[bucket] = entry.limiter
+	}
+	return limiters, func() {
+		sharedLimiters.Lock()
+		defer sharedLimiters.Unlock()
+		for _, key := range keys {
+			if entry, ok := sharedLimiters.limiters[key]; ok {
+				if entry.refs--; entry.refs <= 0 {
+					delete(sharedLimiters.limiters, key)
+				}
+			}
+		}
+	}
+}
+
+// limiterFor returns the limiter of the operation's bucket.
+func (c *Client) limiterFor(op Operation) Limiter {
+	if limiter, ok := c.limiters[op.LimiterBucket()]; ok {
+		return limiter
+	}
+	return c.limiters[LimiterBucketDefault]
+}
+
+// LimiterFor returns the limiter used for a bucket, which is the default limiter
+// for unknown buckets.
+func (c *Client) LimiterFor(bucket string) Limiter {
+	if limiter, ok := c.limiters[bucket]; ok {
+		return limiter
+	}
+	return c.limiters[LimiterBucketDefault]
+}
+
+// RateLimiterFor is LimiterFor for buckets using the built-in *RateLimiter; it
+// returns nil when the bucket uses another Limiter.
+func (c *Client) RateLimiterFor(bucket string) *RateLimiter {
+	limiter, _ := c.LimiterFor(bucket).(*RateLimiter)
+	return limiter
+}
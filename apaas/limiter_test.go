@@ -0,0 +1,369 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAdaptive(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := NewRateLimiter(LimiterOptions{
+		RequestsPerInterval:    8,
+		Interval:               time.Second,
+		Adaptive:               true,
+		MinRequestsPerInterval: 2,
+		RecoveryInterval:       5 * time.Second,
+	})
+	limiter.now = func() time.Time { return now }
+
+	assertRate := func(want float64) {
+		t.Helper()
+		if got := limiter.Rate(); math.Abs(got-want) > 1e-9 {
+			t.Fatalf("expected rate %v, got %v", want, got)
+		}
+	}
+
+	assertRate(8)
+	limiter.throttled()
+	assertRate(4)
+
+	// 同一个周期内的多次限流只降速一次
+	limiter.throttled()
+	assertRate(4)
+
+	now = now.Add(time.Second)
+	limiter.throttled()
+	now = now.Add(time.Second)
+	limiter.throttled()
+	assertRate(2)
+
+	limiter.succeeded()
+	assertRate(2)
+	for i := 0; i < 10; i++ {
+		now = now.Add(5 * time.Second)
+		limiter.succeeded()
+	}
+	assertRate(8)
+}
+
+func TestRateLimiterNotAdaptive(t *testing.T) {
+	limiter := NewRateLimiter(LimiterOptions{RequestsPerInterval: 8, Interval: time.Second})
+	limiter.throttled()
+	if limiter.Rate() != 8 {
+		t.Fatalf("expected fixed rate, got %v", limiter.Rate())
+	}
+}
+
+func TestClientLimitsEveryRequest(t *testing.T) {
+	client := newTestClient(t, ClientOptions{
+		LimiterOptions: &LimiterOptions{RequestsPerInterval: 1, Interval: 50 * time.Millisecond, Burst: 1},
+	}, func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{}})
+	})
+	ctx := context.Background()
+	if err := client.Init(ctx); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.Function.Invoke(ctx, FunctionInvokeParams{Name: "noop"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("expected function calls to be rate limited, took %v", elapsed)
+	}
+}
+
+func TestRecordsWithIteratorStopsOnCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls atomic.Int32
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		cancel()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+
+	ids := make([]string, 500)
+	for i := range ids {
+		ids[i] = "id"
+	}
+	result, err := client.Object.Delete.RecordsWithIterator(ctx, ObjectDeleteRecordsIteratorParams{ObjectName: "order", IDs: ids})
+	if !errors.Is(err, context.Canceled) || result != nil {
+		t.Fatalf("expected the cancellation to abort the loop, got %+v, %v", result, err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single request, got %d", calls.Load())
+	}
+}
+
+func TestRecordsWithIteratorStopsOnLimiterError(t *testing.T) {
+	failing := errors.New("limiter store unavailable")
+	var calls atomic.Int32
+	client := newTestClient(t, ClientOptions{
+		BucketLimiters: map[string]Limiter{LimiterBucketRecordsWrite: failingLimiter{err: failing}},
+	}, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	})
+
+	records := make([]map[string]any, 150)
+	for i := range records {
+		records[i] = map[string]any{"name": "A"}
+	}
+	_, err := client.Object.Create.RecordsWithIterator(context.Background(), ObjectCreateRecordsIteratorParams{ObjectName: "order", Records: records})
+	if !errors.Is(err, failing) || calls.Load() != 0 {
+		t.Fatalf("expected the limiter error to abort the loop, got %v after %d calls", err, calls.Load())
+	}
+}
+
+type failingLimiter struct {
+	err error
+}
+
+func (l failingLimiter) Wait(ctx context.Context) error {
+	return l.err
+}
+
+func TestClientAdaptiveRateOnThrottling(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, ClientOptions{
+		RetryConfig: &RetryConfig{MaxRetries: 0},
+		LimiterBuckets: map[string]LimiterOptions{
+			LimiterBucketMetadata: {RequestsPerInterval: 100, Interval: time.Second, Burst: 10, Adaptive: true},
+		},
+	}, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		writeTestJSON(w, map[string]any{"code": "99991400", "msg": "request trigger frequency limit"})
+	})
+	ctx := context.Background()
+
+	client.Page.Detail(ctx, PageDetailParams{PageID: "page_1"})
+	if rate := client.RateLimiterFor(LimiterBucketMetadata).Rate(); rate != 50 {
+		t.Fatalf("expected rate to be halved after 429, got %v", rate)
+	}
+
+	client.RateLimiterFor(LimiterBucketMetadata).lastDecrease = time.Time{}
+	client.Page.Detail(ctx, PageDetailParams{PageID: "page_1"})
+	if rate := client.RateLimiterFor(LimiterBucketMetadata).Rate(); rate != 25 {
+		t.Fatalf("expected rate to be halved after rate limit code, got %v", rate)
+	}
+}
+
+func TestOperationLimiterBucket(t *testing.T) {
+	cases := map[Operation]string{
+		OpObjectSearchRecords: LimiterBucketRecordsRead,
+		OpObjectCreateRecords: LimiterBucketRecordsWrite,
+		OpObjectDeleteRecord:  LimiterBucketRecordsWrite,
+		OpObjectList:          LimiterBucketMetadata,
+		OpGlobalOptionsList:   LimiterBucketMetadata,
+		OpAutomationV2Execute: LimiterBucketFlow,
+		OpFunctionInvoke:      LimiterBucketFunction,
+		OpAuthToken:           LimiterBucketDefault,
+	}
+	for op, want := range cases {
+		if got := op.LimiterBucket(); got != want {
+			t.Errorf("%s.LimiterBucket() = %s, want %s", op, got, want)
+		}
+	}
+}
+
+func TestClientLimiterBuckets(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		Namespace:    "app_test",
+		ClientID:     "bucket-client",
+		ClientSecret: "secret",
+		Logger:       &discardLogger{},
+		LimiterBuckets: map[string]LimiterOptions{
+			LimiterBucketRecordsWrite: {RequestsPerInterval: 2, Interval: time.Second, Burst: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	write := client.RateLimiterFor(LimiterBucketRecordsWrite)
+	if client.limiterFor(OpObjectCreateRecords) != Limiter(write) || write.Rate() != 2 {
+		t.Fatalf("expected the records.write bucket, got rate %v", write.Rate())
+	}
+	read := client.RateLimiterFor(LimiterBucketRecordsRead)
+	if client.limiterFor(OpObjectSearchRecords) != Limiter(read) || read == client.RateLimiter() || read.Rate() != 5 {
+		t.Fatalf("expected records.read to get its default quota, got rate %v", read.Rate())
+	}
+	if rate := client.RateLimiterFor(LimiterBucketFunction).Rate(); rate != 2 {
+		t.Fatalf("expected the function default quota, got rate %v", rate)
+	}
+}
+
+func TestBucketDefaultsCappedByLimiterOptions(t *testing.T) {
+	slow := LimiterOptions{RequestsPerInterval: 1, Interval: time.Second, Burst: 1, Adaptive: true}
+	opts := bucketDefaults(slow, DefaultLimiterBuckets()[LimiterBucketRecordsRead])
+	if opts.RequestsPerInterval != 1 || opts.Burst != 1 || !opts.Adaptive {
+		t.Fatalf("expected the client options to cap the bucket quota, got %+v", opts)
+	}
+
+	fast := LimiterOptions{RequestsPerInterval: 100, Interval: time.Second, Burst: 50}
+	opts = bucketDefaults(fast, DefaultLimiterBuckets()[LimiterBucketMetadata])
+	if opts.RequestsPerInterval != 2 || opts.Interval != time.Second || opts.Burst != 5 {
+		t.Fatalf("expected the metadata quota, got %+v", opts)
+	}
+}
+
+func TestClientShareLimiters(t *testing.T) {
+	newClient := func(clientID string) *Client {
+		client, err := NewClient(ClientOptions{
+			Namespace:     "app_test",
+			ClientID:      clientID,
+			ClientSecret:  "secret",
+			BaseURL:       "https://shared.example.com",
+			Logger:        &discardLogger{},
+			ShareLimiters: true,
+			LimiterBuckets: map[string]LimiterOptions{
+				LimiterBucketFlow: {RequestsPerInterval: 1, Interval: time.Second},
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		return client
+	}
+
+	a, b, other := newClient("shared-a"), newClient("shared-a"), newClient("shared-b")
+	if a.RateLimiter() != b.RateLimiter() || a.RateLimiterFor(LimiterBucketFlow) != b.RateLimiterFor(LimiterBucketFlow) {
+		t.Fatalf("expected clients with the same credentials to share limiters")
+	}
+	if a.RateLimiter() == other.RateLimiter() {
+		t.Fatalf("expected clients with different credentials to use separate limiters")
+	}
+
+	key := "https://shared.example.com|shared-a|" + LimiterBucketFlow
+	refs := func() int {
+		sharedLimiters.Lock()
+		defer sharedLimiters.Unlock()
+		if entry, ok := sharedLimiters.limiters[key]; ok {
+			return entry.refs
+		}
+		return 0
+	}
+	if refs() != 2 {
+		t.Fatalf("expected two references, got %d", refs())
+	}
+
+	// 未调用 Close 的客户端即使被回收也不会释放引用
+	newClient("shared-a")
+	runtime.GC()
+	runtime.GC()
+	if refs() != 3 {
+		t.Fatalf("expected the references to be kept until Close, got %d", refs())
+	}
+	a.Close()
+	a.Close()
+	b.Close()
+	if refs() != 1 {
+		t.Fatalf("expected Close to release exactly one reference per client, got %d", refs())
+	}
+	sharedLimiters.Lock()
+	for k := range sharedLimiters.limiters {
+		if strings.HasPrefix(k, "https://shared.example.com|shared-a|") {
+			delete(sharedLimiters.limiters, k)
+		}
+	}
+	sharedLimiters.Unlock()
+	other.Close()
+}
+
+func TestRateLimiterHighPriorityLatencyUnderSaturation(t *testing.T) {
+	limiter := NewRateLimiter(LimiterOptions{RequestsPerInterval: 100, Interval: time.Second, Burst: 1})
+	ctx := context.Background()
+	low := WithPriority(ctx, PriorityLow)
+
+	// 40 个低优先级请求需要约 400ms 才能全部放行
+	var released atomic.Int32
+	done := make(chan struct{})
+	for i := 0; i < 40; i++ {
+		go func() {
+			if err := limiter.Wait(low); err == nil {
+				released.Add(1)
+			}
+			done <- struct{}{}
+		}()
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		if err := limiter.Wait(WithPriority(ctx, PriorityHigh)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if latency := time.Since(start); latency > 50*time.Millisecond {
+			t.Fatalf("high priority request waited %v behind bulk work", latency)
+		}
+	}
+	if n := released.Load(); n >= 40 {
+		t.Fatalf("expected bulk work to still be queued, %d released", n)
+	}
+
+	for i := 0; i < 40; i++ {
+		<-done
+	}
+	if n := released.Load(); n != 40 {
+		t.Fatalf("expected all bulk requests to be released eventually, got %d", n)
+	}
+}
+
+func TestRateLimiterReservedTokens(t *testing.T) {
+	limiter := NewRateLimiter(LimiterOptions{RequestsPerInterval: 1, Interval: time.Hour, Burst: 5, ReservedTokens: 2})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	timeout, cancel := context.WithTimeout(WithPriority(ctx, PriorityLow), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected reserved tokens to be kept from low priority, got %v", err)
+	}
+
+	high := WithPriority(ctx, PriorityHigh)
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(high); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	limiter.queueMu.Lock()
+	defer limiter.queueMu.Unlock()
+	if len(limiter.queue) != 0 {
+		t.Fatalf("expected canceled waiter to leave the queue, %d queued", len(limiter.queue))
+	}
+}
+
+func TestPriorityFrom(t *testing.T) {
+	ctx := context.Background()
+	if PriorityFrom(ctx) != PriorityNormal {
+		t.Fatalf("expected normal priority by default")
+	}
+	if PriorityFrom(withBulkPriority(ctx)) != PriorityLow {
+		t.Fatalf("expected bulk work to default to low priority")
+	}
+	if PriorityFrom(withBulkPriority(WithPriority(ctx, PriorityHigh))) != PriorityHigh {
+		t.Fatalf("expected an explicit priority to be kept")
+	}
+}
@@ -0,0 +1,454 @@
+package apaas
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// FieldType identifies the data type of an object field.
+type FieldType string
+
+// Field types known to the SDK. Fields of other types decode with their raw type name.
+const (
+	FieldTypeText         FieldType = "text"
+	FieldTypeMultilingual FieldType = "multilingual"
+	FieldTypeRichText     FieldType = "richText"
+	FieldTypeEmail        FieldType = "email"
+	FieldTypePhone        FieldType = "phone"
+	FieldTypeBigint       FieldType = "bigint"
+	FieldTypeNumber       FieldType = "number"
+	FieldTypeDecimal      FieldType = "decimal"
+	FieldTypeBoolean      FieldType = "boolean"
+	FieldTypeDate         FieldType = "date"
+	FieldTypeDateTime     FieldType = "dateTime"
+	FieldTypeOption       FieldType = "option"
+	FieldTypeLookup       FieldType = "lookup"
+	FieldTypeReference    FieldType = "referenceField"
+	FieldTypeAutoNumber   FieldType = "autoNumber"
+	FieldTypeFormula      FieldType = "formula"
+	FieldTypeRollup       FieldType = "rollup"
+	FieldTypeAttachment   FieldType = "attachment"
+	FieldTypeAvatar       FieldType = "avatarOrLogo"
+	FieldTypeRegion       FieldType = "region"
+)
+
+var knownFieldTypes = map[FieldType]bool{
+	FieldTypeText: true, FieldTypeMultilingual: true, FieldTypeRichText: true, FieldTypeEmail: true,
+	FieldTypePhone: true, FieldTypeBigint: true, FieldTypeNumber: true, FieldTypeDecimal: true,
+	FieldTypeBoolean: true, FieldTypeDate: true, FieldTypeDateTime: true, FieldTypeOption: true,
+	FieldTypeLookup: true, FieldTypeReference: true, FieldTypeAutoNumber: true, FieldTypeFormula: true,
+	FieldTypeRollup: true, FieldTypeAttachment: true, FieldTypeAvatar: true, FieldTypeRegion: true,
+}
+
+// IsKnown reports whether the SDK has a dedicated constant for the type.
+func (t FieldType) IsKnown() bool {
+	return knownFieldTypes[t]
+}
+
+// IsNumeric reports whether values of the type are JSON numbers.
+func (t FieldType) IsNumeric() bool {
+	return t == FieldTypeBigint || t == FieldTypeNumber || t == FieldTypeDecimal
+}
+
+// IsText reports whether values of the type are JSON strings.
+func (t FieldType) IsText() bool {
+	return t == FieldTypeText || t == FieldTypeRichText || t == FieldTypeEmail || t == FieldTypePhone
+}
+
+// MultilingualText maps language codes (e.g. "zh_CN", "en_US") to text.
+type MultilingualText map[string]string
+
+// UnmarshalJSON accepts a plain string, a language map, or a map with numeric language IDs.
+func (m *MultilingualText) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*m = MultilingualText{"": text}
+		return nil
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid multilingual text: %w", err)
+	}
+
+	result := make(MultilingualText, len(raw))
+	for lang, value := range raw {
+		if s, ok := value.(string); ok {
+			result[lang] = s
+		}
+	}
+	*m = result
+	return nil
+}
+
+// Get returns the text for the language, falling back to zh_CN, en_US and any other value.
+func (m MultilingualText) Get(lang string) string {
+	for _, key := range []string{lang, "zh_CN", "en_US", "2052", "1033", ""} {
+		if text, ok := m[key]; ok && text != "" {
+			return text
+		}
+	}
+
+	langs := make([]string, 0, len(m))
+	for key := range m {
+		langs = append(langs, key)
+	}
+	sort.Strings(langs)
+	for _, key := range langs {
+		if m[key] != "" {
+			return m[key]
+		}
+	}
+	return ""
+}
+
+// String returns the preferred text.
+func (m MultilingualText) String() string {
+	return m.Get("")
+}
+
+// Object describes an object (data table) and, when fetched via FieldsTyped, its fields.
+type Object struct {
+	APIName string           `json:"apiName"`
+	Label   MultilingualText `json:"label"`
+	Type    string           `json:"type,omitempty"`
+	Fields  []Field          `json:"fields,omitempty"`
+}
+
+// Field looks up a field by API name.
+func (o *Object) Field(apiName string) (*Field, bool) {
+	if o == nil {
+		return nil, false
+	}
+	for i := range o.Fields {
+		if o.Fields[i].APIName == apiName {
+			return &o.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// ObjectList is a page of objects returned by ListTyped.
+type ObjectList struct {
+	Items []Object `json:"items"`
+	Total int      `json:"total"`
+}
+
+// Field describes an object field.
+type Field struct {
+	APIName       string           `json:"apiName"`
+	Label         MultilingualText `json:"label"`
+	Type          FieldType        `json:"type"`
+	Required      bool             `json:"required"`
+	Unique        bool             `json:"unique"`
+	Multiple      bool             `json:"multiple"`
+	MaxLength     int              `json:"maxLength,omitempty"`
+	DecimalPlaces int              `json:"decimalPlaces,omitempty"`
+	MinValue      *float64         `json:"minValue,omitempty"`
+	MaxValue      *float64         `json:"maxValue,omitempty"`
+	Options       *OptionSet       `json:"options,omitempty"`
+	Lookup        *LookupTarget    `json:"lookup,omitempty"`
+	Formula       *Formula         `json:"formula,omitempty"`
+	// Settings keeps the raw type settings for attributes without a dedicated field.
+	Settings map[string]any `json:"settings,omitempty"`
+}
+
+// OptionSet lists the values allowed by an option field or a global option.
+type OptionSet struct {
+	GlobalOptionAPIName string   `json:"globalOptionApiName,omitempty"`
+	Options             []Option `json:"options"`
+}
+
+// Names returns the API names of the options.
+func (s *OptionSet) Names() []string {
+	if s == nil {
+		return nil
+	}
+	names := make([]string, 0, len(s.Options))
+	for _, option := range s.Options {
+		names = append(names, option.APIName)
+	}
+	return names
+}
+
+// Has reports whether the option set contains an active option with the API name.
+func (s *OptionSet) Has(apiName string) bool {
+	if s == nil {
+		return false
+	}
+	for _, option := range s.Options {
+		if option.APIName == apiName {
+			return option.Active
+		}
+	}
+	return false
+}
+
+// Option is a single value of an option set.
+type Option struct {
+	APIName string           `json:"apiName"`
+	Label   MultilingualText `json:"label"`
+	Color   string           `json:"color,omitempty"`
+	Active  bool             `json:"active"`
+}
+
+// UnmarshalJSON treats a missing active flag as active and accepts "name" as the label.
+func (o *Option) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		APIName string           `json:"apiName"`
+		Label   MultilingualText `json:"label"`
+		Name    MultilingualText `json:"name"`
+		Color   string           `json:"color"`
+		Active  *bool            `json:"active"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	o.APIName = raw.APIName
+	o.Label = raw.Label
+	if len(o.Label) == 0 {
+		o.Label = raw.Name
+	}
+	o.Color = raw.Color
+	o.Active = raw.Active == nil || *raw.Active
+	return nil
+}
+
+// LookupTarget describes the object referenced by a lookup field.
+type LookupTarget struct {
+	ObjectAPIName string `json:"objectApiName"`
+	Multiple      bool   `json:"multiple"`
+}
+
+// Formula describes a formula field.
+type Formula struct {
+	Expression string    `json:"expression"`
+	ReturnType FieldType `json:"returnType,omitempty"`
+}
+
+// UnmarshalJSON decodes the field metadata format returned by the object metadata APIs.
+// The type may be a plain name or a {"name", "settings"} object; unknown types are kept as-is.
+func (f *Field) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		APIName  string           `json:"apiName"`
+		Label    MultilingualText `json:"label"`
+		Type     json.RawMessage  `json:"type"`
+		Required *bool            `json:"required"`
+		Settings map[string]any   `json:"settings"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*f = Field{APIName: raw.APIName, Label: raw.Label, Settings: raw.Settings}
+
+	if len(raw.Type) > 0 {
+		var name string
+		if err := json.Unmarshal(raw.Type, &name); err == nil {
+			f.Type = FieldType(name)
+		} else {
+			var typ struct {
+				Name     string         `json:"name"`
+				Settings map[string]any `json:"settings"`
+			}
+			if err := json.Unmarshal(raw.Type, &typ); err != nil {
+				return fmt.Errorf("invalid type of field %s: %w", raw.APIName, err)
+			}
+			f.Type = FieldType(typ.Name)
+			if typ.Settings != nil {
+				f.Settings = typ.Settings
+			}
+		}
+	}
+
+	settings := f.Settings
+	f.Required = settingBool(settings, "required")
+	if raw.Required != nil {
+		f.Required = *raw.Required
+	}
+	f.Unique = settingBool(settings, "unique", "isUnique")
+	f.Multiple = settingBool(settings, "multiple", "isMultiple")
+	f.MaxLength = int(settingNumber(settings, "maxLength", "max_length"))
+	f.DecimalPlaces = int(settingNumber(settings, "decimalPlaces", "decimalPlacesNumber", "precision"))
+	f.MinValue = settingNumberPtr(settings, "minValue", "min")
+	f.MaxValue = settingNumberPtr(settings, "maxValue", "max")
+
+	switch f.Type {
+	case FieldTypeOption:
+		options := &OptionSet{GlobalOptionAPIName: settingString(settings, "globalOptionApiName", "optionApiName")}
+		if err := remarshal(settingValue(settings, "options", "optionList"), &options.Options); err != nil {
+			return fmt.Errorf("invalid options of field %s: %w", raw.APIName, err)
+		}
+		f.Options = options
+	case FieldTypeLookup, FieldTypeReference:
+		f.Lookup = &LookupTarget{
+			ObjectAPIName: settingString(settings, "referenceObjectApiName", "objectApiName", "lookupObjectApiName"),
+			Multiple:      f.Multiple,
+		}
+	case FieldTypeFormula:
+		f.Formula = &Formula{
+			Expression: settingString(settings, "formula", "expression"),
+			ReturnType: FieldType(settingString(settings, "returnType")),
+		}
+	}
+
+	return nil
+}
+
+// FieldsTyped retrieves an object's metadata with decoded fields.
+func (s *ObjectMetadataService) FieldsTyped(ctx context.Context, params ObjectMetadataFieldsParams) (*Object, error) {
+	resp, err := s.Fields(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var object Object
+	if err := resp.DecodeData(&object); err != nil {
+		return nil, fmt.Errorf("failed to decode object metadata: %w", err)
+	}
+	if object.APIName == "" {
+		object.APIName = params.ObjectName
+	}
+	return &object, nil
+}
+
+// FieldTyped retrieves a single field's decoded metadata.
+func (s *ObjectMetadataService) FieldTyped(ctx context.Context, params ObjectMetadataFieldParams) (*Field, error) {
+	resp, err := s.Field(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var field Field
+	if err := resp.DecodeData(&field); err != nil {
+		return nil, fmt.Errorf("failed to decode field metadata: %w", err)
+	}
+	return &field, nil
+}
+
+// ListTyped returns a page of objects as typed models.
+func (s *ObjectService) ListTyped(ctx context.Context, params ObjectListParams) (*ObjectList, error) {
+	resp, err := s.List(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var list ObjectList
+	if err := resp.DecodeData(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode objects list: %w", err)
+	}
+	return &list, nil
+}
+
+// DetailTyped retrieves a global option's values as an option set.
+func (s *GlobalOptionsService) DetailTyped(ctx context.Context, apiName string) (*OptionSet, error) {
+	resp, err := s.Detail(ctx, apiName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var detail struct {
+		Options    []Option `json:"options"`
+		OptionList []Option `json:"optionList"`
+	}
+	if err := resp.DecodeData(&detail); err != nil {
+		return nil, fmt.Errorf("failed to decode global option: %w", err)
+	}
+
+	set := &OptionSet{GlobalOptionAPIName: apiName, Options: detail.Options}
+	if len(set.Options) == 0 {
+		set.Options = detail.OptionList
+	}
+	return set, nil
+}
+
+// checkResponse converts a non-zero business code into an *APIError.
+func checkResponse(resp *APIResponse) error {
+	if resp == nil {
+		return fmt.Errorf("api response is nil")
+	}
+	if resp.Code != "0" && resp.Code != "" {
+		return &APIError{StatusCode: http.StatusOK, Code: resp.Code, Message: resp.Msg}
+	}
+	return nil
+}
+
+func settingValue(settings map[string]any, keys ...string) any {
+	for _, key := range keys {
+		if value, ok := settings[key]; ok && value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
+func settingBool(settings map[string]any, keys ...string) bool {
+	switch v := settingValue(settings, keys...).(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}
+
+func settingString(settings map[string]any, keys ...string) string {
+	if s, ok := settingValue(settings, keys...).(string); ok {
+		return s
+	}
+	return ""
+}
+
+func settingNumber(settings map[string]any, keys ...string) float64 {
+	if n := settingNumberPtr(settings, keys...); n != nil {
+		return *n
+	}
+	return 0
+}
+
+func settingNumberPtr(settings map[string]any, keys ...string) *float64 {
+	switch v := settingValue(settings, keys...).(type) {
+	case float64:
+		return &v
+	case string:
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return &n
+		}
+	}
+	return nil
+}
+
+// remarshal converts a decoded JSON value into the target type.
+func remarshal(value, target any) error {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
@@ -0,0 +1,88 @@
+package apaas
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestObject_UnmarshalFields(t *testing.T) {
+	raw := `{
+		"apiName": "object_store",
+		"label": {"zh_CN": "门店", "en_US": "Store"},
+		"fields": [
+			{"apiName": "name", "label": {"en_US": "Name"}, "type": {"name": "text", "settings": {"required": true, "maxLength": 100}}},
+			{"apiName": "status", "type": {"name": "option", "settings": {"globalOptionApiName": "option_status", "options": [
+				{"apiName": "open", "name": {"en_US": "Open"}},
+				{"apiName": "closed", "active": false}
+			]}}},
+			{"apiName": "manager", "type": {"name": "lookup", "settings": {"referenceObjectApiName": "_user", "multiple": true}}},
+			{"apiName": "margin", "type": {"name": "formula", "settings": {"formula": "price - cost", "returnType": "number"}}},
+			{"apiName": "score", "type": {"name": "number", "settings": {"minValue": 0, "maxValue": "100", "decimalPlaces": 2}}},
+			{"apiName": "shape", "type": {"name": "geoPolygon", "settings": {"srid": 4326}}}
+		]
+	}`
+
+	var object Object
+	if err := json.Unmarshal([]byte(raw), &object); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+
+	if object.Label.Get("en_US") != "Store" || object.Label.String() != "门店" {
+		t.Errorf("unexpected label: %v", object.Label)
+	}
+
+	name, _ := object.Field("name")
+	if name.Type != FieldTypeText || !name.Required || name.MaxLength != 100 || name.Label.String() != "Name" {
+		t.Errorf("unexpected text field: %+v", name)
+	}
+
+	status, _ := object.Field("status")
+	if status.Options == nil || status.Options.GlobalOptionAPIName != "option_status" {
+		t.Fatalf("unexpected option field: %+v", status)
+	}
+	if !status.Options.Has("open") || status.Options.Has("closed") {
+		t.Errorf("expected only active options to match, got %+v", status.Options.Options)
+	}
+	if status.Options.Options[0].Label.String() != "Open" {
+		t.Errorf("expected option name to be used as label, got %v", status.Options.Options[0].Label)
+	}
+
+	manager, _ := object.Field("manager")
+	if manager.Lookup == nil || manager.Lookup.ObjectAPIName != "_user" || !manager.Lookup.Multiple {
+		t.Errorf("unexpected lookup field: %+v", manager)
+	}
+
+	margin, _ := object.Field("margin")
+	if margin.Formula == nil || margin.Formula.Expression != "price - cost" || margin.Formula.ReturnType != FieldTypeNumber {
+		t.Errorf("unexpected formula field: %+v", margin)
+	}
+
+	score, _ := object.Field("score")
+	if score.MinValue == nil || *score.MinValue != 0 || score.MaxValue == nil || *score.MaxValue != 100 || score.DecimalPlaces != 2 {
+		t.Errorf("unexpected number field: %+v", score)
+	}
+
+	shape, ok := object.Field("shape")
+	if !ok || shape.Type.IsKnown() || shape.Type != "geoPolygon" || shape.Settings["srid"] != float64(4326) {
+		t.Errorf("expected unknown field type to be kept, got %+v", shape)
+	}
+}
+
+func TestField_UnmarshalPlainType(t *testing.T) {
+	var field Field
+	if err := json.Unmarshal([]byte(`{"apiName":"_id","label":"ID","type":"bigint","required":true}`), &field); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if field.Type != FieldTypeBigint || !field.Type.IsNumeric() || !field.Required || field.Label.String() != "ID" {
+		t.Errorf("unexpected field: %+v", field)
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	if err := checkResponse(&APIResponse{Code: "0"}); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+	if code := ErrorCode(checkResponse(&APIResponse{Code: "k_ec_1", Msg: "boom"})); code != "k_ec_1" {
+		t.Errorf("expected error code k_ec_1, got %q", code)
+	}
+}
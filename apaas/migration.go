@@ -0,0 +1,362 @@
+package apaas
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MigrationOptions configures a record migration between two namespaces.
+type MigrationOptions struct {
+	// Source is the client bound to the namespace records are read from.
+	Source *Client
+	// Target is the client bound to the namespace records are written to.
+	Target *Client
+	// ObjectName is the object API name in the source namespace.
+	ObjectName string
+	// TargetObjectName is the object API name in the target namespace. Defaults to ObjectName.
+	TargetObjectName string
+	// KeyFields identify the same logical record in both namespaces (natural key).
+	KeyFields []string
+	// Fields are copied from source to target. Key fields are always included.
+	Fields []string
+	// Data is merged into the source records_query payload (e.g. a filter).
+	Data map[string]any
+	// TargetData is merged into the target records_query payload. Defaults to Data.
+	TargetData map[string]any
+	// Transform rewrites a source record before it is compared with the target,
+	// e.g. to remap lookup IDs. Returning nil skips the record.
+	Transform func(record map[string]any) (map[string]any, error)
+	// DeleteMissing deletes target records whose key is absent from the source.
+	DeleteMissing bool
+	// DryRun computes the plan without writing to the target.
+	DryRun bool
+	// Limit is the batch size used for writes, default 100.
+	Limit int
+}
+
+// MigrationPlan describes the changes required to align the target with the source.
+type MigrationPlan struct {
+	ObjectName       string            `json:"objectName"`
+	TargetObjectName string            `json:"targetObjectName"`
+	Creates          []map[string]any  `json:"creates"`
+	Updates          []MigrationUpdate `json:"updates"`
+	Deletes          []MigrationDelete `json:"deletes"`
+	Unchanged        int               `json:"unchanged"`
+}
+
+// MigrationUpdate is a planned update of an existing target record.
+type MigrationUpdate struct {
+	ID      string                 `json:"_id"`
+	Key     string                 `json:"key"`
+	Changes map[string]FieldChange `json:"changes"`
+}
+
+// MigrationDelete is a planned deletion of a target record.
+type MigrationDelete struct {
+	ID  string `json:"_id"`
+	Key string `json:"key"`
+}
+
+// FieldChange holds the before and after value of a field.
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// MigrationResult reports the plan and, unless DryRun was set, the write outcomes.
+type MigrationResult struct {
+	Plan    *MigrationPlan        `json:"plan"`
+	DryRun  bool                  `json:"dryRun"`
+	Created *BatchOperationResult `json:"created,omitempty"`
+	Updated *BatchOperationResult `json:"updated,omitempty"`
+	Deleted *BatchOperationResult `json:"deleted,omitempty"`
+}
+
+// HasChanges reports whether applying the plan would modify the target.
+func (p *MigrationPlan) HasChanges() bool {
+	return p != nil && (len(p.Creates) > 0 || len(p.Updates) > 0 || len(p.Deletes) > 0)
+}
+
+// String renders the plan as a human readable summary.
+func (p *MigrationPlan) String() string {
+	if p == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "migration %s -> %s: create=%d, update=%d, delete=%d, unchanged=%d\n",
+		p.ObjectName, p.TargetObjectName, len(p.Creates), len(p.Updates), len(p.Deletes), p.Unchanged)
+
+	for _, record := range p.Creates {
+		fmt.Fprintf(&b, "  + create %s\n", mustJSON(record))
+	}
+	for _, update := range p.Updates {
+		fields := make([]string, 0, len(update.Changes))
+		for field := range update.Changes {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		fmt.Fprintf(&b, "  ~ update %s key=%s\n", update.ID, update.Key)
+		for _, field := range fields {
+			change := update.Changes[field]
+			fmt.Fprintf(&b, "      %s: %s -> %s\n", field, mustJSON(change.From), mustJSON(change.To))
+		}
+	}
+	for _, del := range p.Deletes {
+		fmt.Fprintf(&b, "  - delete %s key=%s\n", del.ID, del.Key)
+	}
+	return b.String()
+}
+
+// Migrate copies records of an object from the source namespace to the target namespace.
+// Records are matched by KeyFields; missing records are created, changed records are
+// updated and, with DeleteMissing, target-only records are deleted.
+func Migrate(ctx context.Context, opts MigrationOptions) (*MigrationResult, error) {
+	if opts.Source == nil || opts.Target == nil {
+		return nil, fmt.Errorf("source and target clients are required")
+	}
+	if opts.ObjectName == "" {
+		return nil, fmt.Errorf("object name is required")
+	}
+	if len(opts.KeyFields) == 0 {
+		return nil, fmt.Errorf("at least one key field is required")
+	}
+	if len(opts.Fields) == 0 {
+		return nil, fmt.Errorf("at least one field is required")
+	}
+
+	targetObject := opts.TargetObjectName
+	if targetObject == "" {
+		targetObject = opts.ObjectName
+	}
+	targetData := opts.TargetData
+	if targetData == nil {
+		targetData = opts.Data
+	}
+
+	fields := migrationFields(opts.KeyFields, opts.Fields)
+	selectFields := append([]string{"_id"}, fields...)
+
+	opts.Source.log(LoggerLevelInfo, "[migration] Reading source records: %s", opts.ObjectName)
+	source, err := opts.Source.Object.Search.RecordsWithIterator(ctx, ObjectRecordsIteratorParams{
+		ObjectName: opts.ObjectName,
+		Data:       migrationQuery(opts.Data, selectFields),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source records: %w", err)
+	}
+
+	opts.Target.log(LoggerLevelInfo, "[migration] Reading target records: %s", targetObject)
+	target, err := opts.Target.Object.Search.RecordsWithIterator(ctx, ObjectRecordsIteratorParams{
+		ObjectName: targetObject,
+		Data:       migrationQuery(targetData, selectFields),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target records: %w", err)
+	}
+
+	sourceRecords := source.Items
+	if opts.Transform != nil {
+		sourceRecords = make([]map[string]any, 0, len(source.Items))
+		for _, record := range source.Items {
+			transformed, err := opts.Transform(record)
+			if err != nil {
+				return nil, fmt.Errorf("failed to transform source record %v: %w", record["_id"], err)
+			}
+			if transformed != nil {
+				sourceRecords = append(sourceRecords, transformed)
+			}
+		}
+	}
+
+	plan, err := buildMigrationPlan(sourceRecords, target.Items, opts.KeyFields, fields, opts.DeleteMissing)
+	if err != nil {
+		return nil, err
+	}
+	plan.ObjectName = opts.ObjectName
+	plan.TargetObjectName = targetObject
+
+	opts.Target.log(LoggerLevelInfo, "[migration] Plan computed: create=%d, update=%d, delete=%d, unchanged=%d",
+		len(plan.Creates), len(plan.Updates), len(plan.Deletes), plan.Unchanged)
+
+	result := &MigrationResult{Plan: plan, DryRun: opts.DryRun}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if len(plan.Creates) > 0 {
+		result.Created, err = opts.Target.Object.Create.RecordsWithIterator(ctx, ObjectCreateRecordsIteratorParams{
+			ObjectName: targetObject,
+			Records:    plan.Creates,
+			Limit:      opts.Limit,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to create target records: %w", err)
+		}
+	}
+
+	if len(plan.Updates) > 0 {
+		records := make([]map[string]any, 0, len(plan.Updates))
+		for _, update := range plan.Updates {
+			record := map[string]any{"_id": update.ID}
+			for field, change := range update.Changes {
+				record[field] = change.To
+			}
+			records = append(records, record)
+		}
+		result.Updated, err = opts.Target.Object.Update.RecordsWithIterator(ctx, ObjectUpdateRecordsIteratorParams{
+			ObjectName: targetObject,
+			Records:    records,
+			Limit:      opts.Limit,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to update target records: %w", err)
+		}
+	}
+
+	if len(plan.Deletes) > 0 {
+		ids := make([]string, 0, len(plan.Deletes))
+		for _, del := range plan.Deletes {
+			ids = append(ids, del.ID)
+		}
+		result.Deleted, err = opts.Target.Object.Delete.RecordsWithIterator(ctx, ObjectDeleteRecordsIteratorParams{
+			ObjectName: targetObject,
+			IDs:        ids,
+			Limit:      opts.Limit,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to delete target records: %w", err)
+		}
+	}
+
+	opts.Target.log(LoggerLevelInfo, "[migration] Migration completed: %s -> %s", opts.ObjectName, targetObject)
+	return result, nil
+}
+
+// buildMigrationPlan diffs source records against target records by natural key.
+func buildMigrationPlan(source, target []map[string]any, keyFields, fields []string, deleteMissing bool) (*MigrationPlan, error) {
+	plan := &MigrationPlan{
+		Creates: make([]map[string]any, 0),
+		Updates: make([]MigrationUpdate, 0),
+		Deletes: make([]MigrationDelete, 0),
+	}
+
+	targetByKey := make(map[string]map[string]any, len(target))
+	for _, record := range target {
+		key, err := recordKey(record, keyFields)
+		if err != nil {
+			return nil, fmt.Errorf("target record %v: %w", record["_id"], err)
+		}
+		if _, exists := targetByKey[key]; exists {
+			return nil, fmt.Errorf("duplicate key %s in target records", key)
+		}
+		targetByKey[key] = record
+	}
+
+	seen := make(map[string]bool, len(source))
+	for _, record := range source {
+		key, err := recordKey(record, keyFields)
+		if err != nil {
+			return nil, fmt.Errorf("source record %v: %w", record["_id"], err)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate key %s in source records", key)
+		}
+		seen[key] = true
+
+		existing, ok := targetByKey[key]
+		if !ok {
+			create := make(map[string]any, len(fields))
+			for _, field := range fields {
+				if value, ok := record[field]; ok {
+					create[field] = value
+				}
+			}
+			plan.Creates = append(plan.Creates, create)
+			continue
+		}
+
+		changes := make(map[string]FieldChange)
+		for _, field := range fields {
+			value, ok := record[field]
+			if !ok {
+				continue
+			}
+			if !jsonEqual(value, existing[field]) {
+				changes[field] = FieldChange{From: existing[field], To: value}
+			}
+		}
+
+		if len(changes) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		plan.Updates = append(plan.Updates, MigrationUpdate{
+			ID:      recordID(existing),
+			Key:     key,
+			Changes: changes,
+		})
+	}
+
+	if deleteMissing {
+		for _, record := range target {
+			key, _ := recordKey(record, keyFields)
+			if !seen[key] {
+				plan.Deletes = append(plan.Deletes, MigrationDelete{ID: recordID(record), Key: key})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// migrationFields returns key fields followed by the remaining copied fields, without duplicates.
+func migrationFields(keyFields, fields []string) []string {
+	seen := make(map[string]bool, len(keyFields)+len(fields))
+	result := make([]string, 0, len(keyFields)+len(fields))
+	for _, field := range append(append([]string{}, keyFields...), fields...) {
+		if field == "" || field == "_id" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		result = append(result, field)
+	}
+	return result
+}
+
+func migrationQuery(data map[string]any, selectFields []string) map[string]any {
+	query := cloneMap(data)
+	query["select"] = selectFields
+	if _, ok := query["page_size"]; !ok {
+		query["page_size"] = 100
+	}
+	return query
+}
+
+// recordKey builds a comparable natural key from the given fields.
+func recordKey(record map[string]any, keyFields []string) (string, error) {
+	values := make([]any, 0, len(keyFields))
+	for _, field := range keyFields {
+		value, ok := record[field]
+		if !ok || value == nil {
+			return "", fmt.Errorf("missing key field %s", field)
+		}
+		values = append(values, value)
+	}
+	return mustJSON(values), nil
+}
+
+// recordID returns the record's _id as a string.
+func recordID(record map[string]any) string {
+	switch id := record["_id"].(type) {
+	case string:
+		return id
+	case nil:
+		return ""
+	default:
+		return mustJSON(id)
+	}
+}
@@ -0,0 +1,80 @@
+package apaas
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildMigrationPlan(t *testing.T) {
+	source := []map[string]any{
+		{"_id": "s1", "code": "A", "name": "Alpha", "rank": 1},
+		{"_id": "s2", "code": "B", "name": "Beta", "rank": 2},
+		{"_id": "s3", "code": "C", "name": "Gamma", "rank": 3},
+	}
+	target := []map[string]any{
+		{"_id": "t1", "code": "A", "name": "Alpha", "rank": float64(1)},
+		{"_id": "t2", "code": "B", "name": "Old Beta", "rank": float64(2)},
+		{"_id": "t4", "code": "D", "name": "Delta", "rank": float64(4)},
+	}
+
+	plan, err := buildMigrationPlan(source, target, []string{"code"}, []string{"code", "name", "rank"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if plan.Unchanged != 1 {
+		t.Errorf("expected 1 unchanged record, got %d", plan.Unchanged)
+	}
+	if len(plan.Creates) != 1 || plan.Creates[0]["code"] != "C" {
+		t.Errorf("expected create for C, got %+v", plan.Creates)
+	}
+	if _, ok := plan.Creates[0]["_id"]; ok {
+		t.Error("create must not carry the source _id")
+	}
+	if len(plan.Updates) != 1 || plan.Updates[0].ID != "t2" {
+		t.Fatalf("expected update for t2, got %+v", plan.Updates)
+	}
+	if change, ok := plan.Updates[0].Changes["name"]; !ok || change.To != "Beta" {
+		t.Errorf("expected name change to Beta, got %+v", plan.Updates[0].Changes)
+	}
+	if _, ok := plan.Updates[0].Changes["rank"]; ok {
+		t.Error("int and float64 values of the same number must compare equal")
+	}
+	if len(plan.Deletes) != 1 || plan.Deletes[0].ID != "t4" {
+		t.Errorf("expected delete for t4, got %+v", plan.Deletes)
+	}
+	if !strings.Contains(plan.String(), "create=1, update=1, delete=1, unchanged=1") {
+		t.Errorf("unexpected plan summary: %s", plan.String())
+	}
+}
+
+func TestBuildMigrationPlan_WithoutDeleteMissing(t *testing.T) {
+	target := []map[string]any{{"_id": "t1", "code": "A"}}
+
+	plan, err := buildMigrationPlan(nil, target, []string{"code"}, []string{"code"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.HasChanges() {
+		t.Errorf("expected no changes, got %+v", plan)
+	}
+}
+
+func TestBuildMigrationPlan_DuplicateKey(t *testing.T) {
+	source := []map[string]any{
+		{"_id": "s1", "code": "A"},
+		{"_id": "s2", "code": "A"},
+	}
+
+	if _, err := buildMigrationPlan(source, nil, []string{"code"}, []string{"code"}, false); err == nil {
+		t.Error("expected duplicate key error, got nil")
+	}
+}
+
+func TestBuildMigrationPlan_MissingKey(t *testing.T) {
+	source := []map[string]any{{"_id": "s1", "name": "no code"}}
+
+	if _, err := buildMigrationPlan(source, nil, []string{"code"}, []string{"code"}, false); err == nil {
+		t.Error("expected missing key error, got nil")
+	}
+}
@@ -2,6 +2,7 @@ package apaas
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -72,12 +73,14 @@ type ObjectSearchRecordParams struct {
 type ObjectSearchRecordsParams struct {
 	ObjectName string
 	Data       map[string]any
+	Filter     *RecordFilter // 可选，与 Data 中的 filter 互斥
 }
 
 // ObjectRecordsIteratorParams fetches all records via pagination.
 type ObjectRecordsIteratorParams struct {
 	ObjectName string
 	Data       map[string]any
+	Filter     *RecordFilter // 可选，与 Data 中的 filter 互斥
 }
 
 // ObjectCreateService inserts records.
@@ -180,29 +183,41 @@ func (s *ObjectService) List(ctx context.Context, params ObjectListParams) (*API
 	return resp, nil
 }
 
+// ListIterator lazily pages through all objects. Use PaginatorAs[Object] for typed items.
+func (s *ObjectService) ListIterator(ctx context.Context, filter *ObjectListFilter, opts PaginatorOptions) *Paginator[map[string]any] {
+	fetch := func(ctx context.Context, req PageRequest) (*Page, error) {
+		resp, err := s.List(ctx, ObjectListParams{Offset: req.Offset, Limit: req.Limit, Filter: filter})
+		if err != nil {
+			return nil, err
+		}
+		return decodePage(resp)
+	}
+	return newPaginator[map[string]any](ctx, s.client, "object.listIterator", PaginationOffset, fetch, opts)
+}
+
 // Field retrieves metadata for a specific field.
+// The response is served from the metadata cache when it is enabled.
 func (s *ObjectMetadataService) Field(ctx context.Context, params ObjectMetadataFieldParams) (*APIResponse, error) {
-	var resp *APIResponse
-
-	err := s.client.limiter.Do(ctx, func() error {
-		if err := s.client.ensureTokenValid(ctx); err != nil {
-			return err
-		}
+	return s.client.metadataCache.fetch(ctx, fieldCacheKey(params.ObjectName, params.FieldName), func(ctx context.Context) (*APIResponse, error) {
+		return s.field(ctx, params)
+	})
+}
 
-		endpoint := fmt.Sprintf(
-			"/api/data/v1/namespaces/%s/meta/objects/%s/fields/%s",
-			url.PathEscape(s.client.namespace),
-			url.PathEscape(params.ObjectName),
-			url.PathEscape(params.FieldName),
-		)
+func (s *ObjectMetadataService) field(ctx context.Context, params ObjectMetadataFieldParams) (*APIResponse, error) {
+	if err := s.client.ensureTokenValid(ctx); err != nil {
+		return nil, err
+	}
 
-		s.client.log(LoggerLevelDebug, "[object.metadata.field] Fetching field metadata: %s.%s", params.ObjectName, params.FieldName)
+	endpoint := fmt.Sprintf(
+		"/api/data/v1/namespaces/%s/meta/objects/%s/fields/%s",
+		url.PathEscape(s.client.namespace),
+		url.PathEscape(params.ObjectName),
+		url.PathEscape(params.FieldName),
+	)
 
-		var err error
-		resp, err = s.client.doJSON(ctx, http.MethodGet, endpoint, nil, true, nil)
-		return err
-	})
+	s.client.log(LoggerLevelDebug, "[object.metadata.field] Fetching field metadata: %s.%s", params.ObjectName, params.FieldName)
 
+	resp, err := s.client.doJSON(ctx, http.MethodGet, endpoint, nil, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -212,27 +227,27 @@ func (s *ObjectMetadataService) Field(ctx context.Context, params ObjectMetadata
 }
 
 // Fields retrieves metadata for all fields on an object.
+// The response is served from the metadata cache when it is enabled.
 func (s *ObjectMetadataService) Fields(ctx context.Context, params ObjectMetadataFieldsParams) (*APIResponse, error) {
-	var resp *APIResponse
-
-	err := s.client.limiter.Do(ctx, func() error {
-		if err := s.client.ensureTokenValid(ctx); err != nil {
-			return err
-		}
+	return s.client.metadataCache.fetch(ctx, fieldsCacheKey(params.ObjectName), func(ctx context.Context) (*APIResponse, error) {
+		return s.fields(ctx, params)
+	})
+}
 
-		endpoint := fmt.Sprintf(
-			"/api/data/v1/namespaces/%s/meta/objects/%s",
-			url.PathEscape(s.client.namespace),
-			url.PathEscape(params.ObjectName),
-		)
+func (s *ObjectMetadataService) fields(ctx context.Context, params ObjectMetadataFieldsParams) (*APIResponse, error) {
+	if err := s.client.ensureTokenValid(ctx); err != nil {
+		return nil, err
+	}
 
-		s.client.log(LoggerLevelDebug, "[object.metadata.fields] Fetching all fields metadata: %s", params.ObjectName)
+	endpoint := fmt.Sprintf(
+		"/api/data/v1/namespaces/%s/meta/objects/%s",
+		url.PathEscape(s.client.namespace),
+		url.PathEscape(params.ObjectName),
+	)
 
-		var err error
-		resp, err = s.client.doJSON(ctx, http.MethodGet, endpoint, nil, true, nil)
-		return err
-	})
+	s.client.log(LoggerLevelDebug, "[object.metadata.fields] Fetching all fields metadata: %s", params.ObjectName)
 
+	resp, err := s.client.doJSON(ctx, http.MethodGet, endpoint, nil, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -245,27 +260,22 @@ func (s *ObjectMetadataService) Fields(ctx context.Context, params ObjectMetadat
 func (s *ObjectSearchService) Record(ctx context.Context, params ObjectSearchRecordParams) (*APIResponse, error) {
 	s.client.log(LoggerLevelInfo, "[object.search.record] Querying record: %s", params.RecordID)
 
-	var resp *APIResponse
-	err := s.client.limiter.Do(ctx, func() error {
-		if err := s.client.ensureTokenValid(ctx); err != nil {
-			return err
-		}
+	if err := s.client.ensureTokenValid(ctx); err != nil {
+		return nil, err
+	}
 
-		endpoint := fmt.Sprintf(
-			"/v1/data/namespaces/%s/objects/%s/records/%s",
-			url.PathEscape(s.client.namespace),
-			url.PathEscape(params.ObjectName),
-			url.PathEscape(params.RecordID),
-		)
+	endpoint := fmt.Sprintf(
+		"/v1/data/namespaces/%s/objects/%s/records/%s",
+		url.PathEscape(s.client.namespace),
+		url.PathEscape(params.ObjectName),
+		url.PathEscape(params.RecordID),
+	)
 
-		payload := map[string]any{
-			"select": params.Select,
-		}
+	payload := map[string]any{
+		"select": params.Select,
+	}
 
-		var err error
-		resp, err = s.client.doJSON(ctx, http.MethodPost, endpoint, payload, true, nil)
-		return err
-	})
+	resp, err := s.client.doJSON(ctx, http.MethodPost, endpoint, payload, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -286,7 +296,12 @@ func (s *ObjectSearchService) Records(ctx context.Context, params ObjectSearchRe
 		url.PathEscape(params.ObjectName),
 	)
 
-	resp, err := s.client.doJSON(ctx, http.MethodPost, endpoint, params.Data, true, nil)
+	payload, err := applyFilter(params.Data, params.ObjectName, params.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.doJSON(ctx, http.MethodPost, endpoint, payload, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -297,78 +312,68 @@ func (s *ObjectSearchService) Records(ctx context.Context, params ObjectSearchRe
 
 // RecordsWithIterator gathers all records using pagination.
 func (s *ObjectSearchService) RecordsWithIterator(ctx context.Context, params ObjectRecordsIteratorParams) (*RecordsIteratorResult, error) {
-	results := &RecordsIteratorResult{
-		Items: make([]map[string]any, 0),
+	ctx = withBulkPriority(ctx)
+
+	paginator := s.recordsIterator(ctx, "object.search.recordsWithIterator", params, PaginatorOptions{})
+	items, err := paginator.Collect()
+	if err != nil {
+		return nil, err
 	}
+	return &RecordsIteratorResult{Total: paginator.Total(), Items: items}, nil
+}
+
+// RecordsIterator lazily pages through the matching records. opts.PageSize overrides
+// page_size in params.Data.
+func (s *ObjectSearchService) RecordsIterator(ctx context.Context, params ObjectRecordsIteratorParams, opts PaginatorOptions) *Paginator[map[string]any] {
+	return s.recordsIterator(ctx, "object.search.recordsIterator", params, opts)
+}
 
-	nextToken := ""
+func (s *ObjectSearchService) recordsIterator(ctx context.Context, operation string, params ObjectRecordsIteratorParams, opts PaginatorOptions) *Paginator[map[string]any] {
+	_, hasPageSize := params.Data["page_size"]
+	explicitPageSize := opts.PageSize > 0
 
-	for {
+	fetch := func(ctx context.Context, req PageRequest) (*Page, error) {
 		requestPayload := cloneMap(params.Data)
-		requestPayload["page_token"] = nextToken
-
-		var resp *APIResponse
-		err := s.client.limiter.Do(ctx, func() error {
-			var err error
-			resp, err = s.Records(ctx, ObjectSearchRecordsParams{
-				ObjectName: params.ObjectName,
-				Data:       requestPayload,
-			})
-			return err
+		requestPayload["page_token"] = req.PageToken
+		if explicitPageSize || !hasPageSize {
+			requestPayload["page_size"] = req.Limit
+		}
+
+		resp, err := s.Records(ctx, ObjectSearchRecordsParams{
+			ObjectName: params.ObjectName,
+			Data:       requestPayload,
+			Filter:     params.Filter,
 		})
 		if err != nil {
 			return nil, err
 		}
-
-		var page struct {
-			Items         []map[string]any `json:"items"`
-			Total         int              `json:"total"`
-			NextPageToken string           `json:"next_page_token"`
-		}
-		if err := resp.DecodeData(&page); err != nil {
-			return nil, fmt.Errorf("failed to decode paginated records: %w", err)
-		}
-
-		if results.Total == 0 && page.Total > 0 {
-			results.Total = page.Total
-		}
-		if len(page.Items) > 0 {
-			results.Items = append(results.Items, page.Items...)
-		}
-
-		s.client.log(LoggerLevelInfo, "[object.search.recordsWithIterator] Page completed: items=%d, next=%s", len(page.Items), page.NextPageToken)
-
-		if page.NextPageToken == "" {
-			break
-		}
-		nextToken = page.NextPageToken
+		return decodePage(resp)
 	}
 
-	return results, nil
+	return newPaginator[map[string]any](ctx, s.client, operation, PaginationToken, fetch, opts)
 }
 
 // Record creates a single record.
 func (s *ObjectCreateService) Record(ctx context.Context, params ObjectCreateRecordParams) (*APIResponse, error) {
 	s.client.log(LoggerLevelInfo, "[object.create.record] Creating record in: %s", params.ObjectName)
 
-	var resp *APIResponse
-	err := s.client.limiter.Do(ctx, func() error {
-		if err := s.client.ensureTokenValid(ctx); err != nil {
-			return err
-		}
+	if err := s.client.preflight(ctx, params.ObjectName, []map[string]any{params.Record}, false); err != nil {
+		return nil, err
+	}
 
-		endpoint := fmt.Sprintf(
-			"/v1/data/namespaces/%s/objects/%s/records",
-			url.PathEscape(s.client.namespace),
-			url.PathEscape(params.ObjectName),
-		)
+	if err := s.client.ensureTokenValid(ctx); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf(
+		"/v1/data/namespaces/%s/objects/%s/records",
+		url.PathEscape(s.client.namespace),
+		url.PathEscape(params.ObjectName),
+	)
 
-		payload := map[string]any{"record": params.Record}
+	payload := map[string]any{"record": params.Record}
 
-		var err error
-		resp, err = s.client.doJSON(ctx, http.MethodPost, endpoint, payload, true, nil)
-		return err
-	})
+	resp, err := s.client.doJSON(ctx, http.MethodPost, endpoint, payload, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -379,6 +384,9 @@ func (s *ObjectCreateService) Record(ctx context.Context, params ObjectCreateRec
 
 // Records creates up to 100 records in a single request.
 func (s *ObjectCreateService) Records(ctx context.Context, params ObjectCreateRecordsParams) (*APIResponse, error) {
+	if err := s.client.preflight(ctx, params.ObjectName, params.Records, false); err != nil {
+		return nil, err
+	}
 	if err := s.client.ensureTokenValid(ctx); err != nil {
 		return nil, err
 	}
@@ -401,6 +409,8 @@ func (s *ObjectCreateService) Records(ctx context.Context, params ObjectCreateRe
 
 // RecordsWithIterator creates records in batches of 100.
 func (s *ObjectCreateService) RecordsWithIterator(ctx context.Context, params ObjectCreateRecordsIteratorParams) (*BatchOperationResult, error) {
+	ctx = withBulkPriority(ctx)
+
 	total := len(params.Records)
 
 	// 参数校验
@@ -425,6 +435,15 @@ func (s *ObjectCreateService) RecordsWithIterator(ctx context.Context, params Ob
 		Failed:  make([]OperationItem, 0),
 	}
 
+	// 预校验全部记录，避免部分批次已写入后才发现错误
+	if err := s.client.preflight(ctx, params.ObjectName, params.Records, false); err != nil {
+		return nil, err
+	}
+	ctx = withPreflightDone(ctx)
+
+	tracker := newProgressTracker(ctx, "object.create.recordsWithIterator")
+	tracker.setTotal(total, (total+chunkSize-1)/chunkSize)
+
 	s.client.log(LoggerLevelDebug, "[object.create.recordsWithIterator] Chunking %d records into groups of %d", total, chunkSize)
 
 	for index := 0; index < total; index += chunkSize {
@@ -437,169 +456,182 @@ func (s *ObjectCreateService) RecordsWithIterator(ctx context.Context, params Ob
 
 		s.client.log(LoggerLevelDebug, "[object.create.recordsWithIterator] Processing chunk %d/%d: %d records", chunkIndex, (total+chunkSize-1)/chunkSize, len(chunk))
 
-		err := s.client.limiter.Do(ctx, func() error {
-			resp, err := s.Records(ctx, ObjectCreateRecordsParams{
-				ObjectName: params.ObjectName,
-				Records:    chunk,
-			})
+		if err := s.createChunk(ctx, params.ObjectName, chunk, chunkIndex, result); err != nil {
+			return nil, err
+		}
 
-			if err != nil {
-				s.client.log(LoggerLevelError, "[object.create.recordsWithIterator] Chunk %d threw error: %v", chunkIndex, err)
-				// 整个批次异常，将这批次的所有记录标记为失败
-				for _, record := range chunk {
-					id := "unknown"
-					if idVal, ok := record["_id"]; ok {
-						if idStr, ok := idVal.(string); ok {
-							id = idStr
-						}
-					}
-					result.Failed = append(result.Failed, OperationItem{
-						ID:      id,
-						Success: false,
-						Error:   err.Error(),
-					})
+		tracker.step(end, len(result.Success), len(result.Failed))
+	}
+
+	result.SuccessCount = len(result.Success)
+	result.FailedCount = len(result.Failed)
+	tracker.finish()
+
+	s.client.log(LoggerLevelInfo, "[object.create.recordsWithIterator] Create completed: total=%d, success=%d, failed=%d", result.Total, result.SuccessCount, result.FailedCount)
+
+	return result, nil
+}
+
+// batchAborted reports whether err must end a batch loop instead of failing one
+// chunk: the caller's context is done or the request could not pass the limiter.
+func batchAborted(ctx context.Context, err error) bool {
+	var limiterErr *limiterError
+	return ctx.Err() != nil || errors.As(err, &limiterErr)
+}
+
+// createChunk sends one chunk and records its outcome in result. Per-chunk API
+// failures are added to result.Failed; errors that abort the batch are returned.
+func (s *ObjectCreateService) createChunk(ctx context.Context, objectName string, chunk []map[string]any, chunkIndex int, result *BatchOperationResult) error {
+	resp, err := s.Records(ctx, ObjectCreateRecordsParams{
+		ObjectName: objectName,
+		Records:    chunk,
+	})
+
+	if err != nil {
+		if batchAborted(ctx, err) {
+			return err
+		}
+		s.client.log(LoggerLevelError, "[object.create.recordsWithIterator] Chunk %d threw error: %v", chunkIndex, err)
+		// 整个批次异常，将这批次的所有记录标记为失败
+		for _, record := range chunk {
+			id := "unknown"
+			if idVal, ok := record["_id"]; ok {
+				if idStr, ok := idVal.(string); ok {
+					id = idStr
 				}
-				return nil // 继续处理下一批次
 			}
+			result.Failed = append(result.Failed, OperationItem{
+				ID:      id,
+				Success: false,
+				Error:   err.Error(),
+			})
+		}
+		return nil // 继续处理下一批次
+	}
 
-			if resp.Code != "0" {
-				s.client.log(LoggerLevelError, "[object.create.recordsWithIterator] Chunk %d failed: code=%s, msg=%s", chunkIndex, resp.Code, resp.Msg)
-				// 整个批次失败，将这批次的所有记录标记为失败
-				for _, record := range chunk {
-					id := "unknown"
-					if idVal, ok := record["_id"]; ok {
-						if idStr, ok := idVal.(string); ok {
-							id = idStr
-						}
-					}
-					errMsg := resp.Msg
-					if errMsg == "" {
-						errMsg = fmt.Sprintf("Creation failed with code %s", resp.Code)
-					}
-					result.Failed = append(result.Failed, OperationItem{
-						ID:      id,
-						Success: false,
-						Error:   errMsg,
-					})
+	if resp.Code != "0" {
+		s.client.log(LoggerLevelError, "[object.create.recordsWithIterator] Chunk %d failed: code=%s, msg=%s", chunkIndex, resp.Code, resp.Msg)
+		// 整个批次失败，将这批次的所有记录标记为失败
+		for _, record := range chunk {
+			id := "unknown"
+			if idVal, ok := record["_id"]; ok {
+				if idStr, ok := idVal.(string); ok {
+					id = idStr
 				}
-				return nil // 继续处理下一批次
 			}
-
-			// 处理响应中的 items
-			var page struct {
-				Items []map[string]any `json:"items"`
+			errMsg := resp.Msg
+			if errMsg == "" {
+				errMsg = fmt.Sprintf("Creation failed with code %s", resp.Code)
 			}
-			if err := resp.DecodeData(&page); err != nil {
-				s.client.log(LoggerLevelError, "[object.create.recordsWithIterator] Failed to decode batch create response: %v", err)
-				for _, record := range chunk {
-					id := "unknown"
-					if idVal, ok := record["_id"]; ok {
-						if idStr, ok := idVal.(string); ok {
-							id = idStr
-						}
-					}
-					result.Failed = append(result.Failed, OperationItem{
-						ID:      id,
-						Success: false,
-						Error:   err.Error(),
-					})
+			result.Failed = append(result.Failed, OperationItem{
+				ID:      id,
+				Success: false,
+				Error:   errMsg,
+			})
+		}
+		return nil // 继续处理下一批次
+	}
+
+	// 处理响应中的 items
+	var page struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := resp.DecodeData(&page); err != nil {
+		s.client.log(LoggerLevelError, "[object.create.recordsWithIterator] Failed to decode batch create response: %v", err)
+		for _, record := range chunk {
+			id := "unknown"
+			if idVal, ok := record["_id"]; ok {
+				if idStr, ok := idVal.(string); ok {
+					id = idStr
 				}
-				return nil
 			}
+			result.Failed = append(result.Failed, OperationItem{
+				ID:      id,
+				Success: false,
+				Error:   err.Error(),
+			})
+		}
+		return nil
+	}
 
-			if len(page.Items) > 0 {
-				for _, item := range page.Items {
-					id := "unknown"
-					if idVal, ok := item["_id"]; ok {
-						if idStr, ok := idVal.(string); ok {
-							id = idStr
-						}
-					}
-
-					// Check success field - if not present or not false, treat as success
-					success := true
-					if successVal, ok := item["success"]; ok {
-						if successBool, ok := successVal.(bool); ok {
-							success = successBool
-						}
-					}
+	if len(page.Items) > 0 {
+		for _, item := range page.Items {
+			id := "unknown"
+			if idVal, ok := item["_id"]; ok {
+				if idStr, ok := idVal.(string); ok {
+					id = idStr
+				}
+			}
 
-					if success {
-						result.Success = append(result.Success, OperationItem{
-							ID:      id,
-							Success: true,
-						})
-					} else {
-						errMsg := ""
-						if errorVal, ok := item["error"]; ok {
-							if errorStr, ok := errorVal.(string); ok {
-								errMsg = errorStr
-							}
-						}
-						result.Failed = append(result.Failed, OperationItem{
-							ID:      id,
-							Success: false,
-							Error:   errMsg,
-						})
-					}
+			// Check success field - if not present or not false, treat as success
+			success := true
+			if successVal, ok := item["success"]; ok {
+				if successBool, ok := successVal.(bool); ok {
+					success = successBool
 				}
 			}
 
-			successCount := 0
-			failedCount := 0
-			for _, item := range page.Items {
-				if successVal, ok := item["success"]; ok {
-					if successBool, ok := successVal.(bool); ok && !successBool {
-						failedCount++
-					} else {
-						successCount++
+			if success {
+				result.Success = append(result.Success, OperationItem{
+					ID:      id,
+					Success: true,
+				})
+			} else {
+				errMsg := ""
+				if errorVal, ok := item["error"]; ok {
+					if errorStr, ok := errorVal.(string); ok {
+						errMsg = errorStr
 					}
-				} else {
-					successCount++
 				}
+				result.Failed = append(result.Failed, OperationItem{
+					ID:      id,
+					Success: false,
+					Error:   errMsg,
+				})
 			}
-
-			s.client.log(LoggerLevelInfo, "[object.create.recordsWithIterator] Chunk %d completed: %s, success=%d, failed=%d", chunkIndex, params.ObjectName, successCount, failedCount)
-			s.client.log(LoggerLevelTrace, "[object.create.recordsWithIterator] Chunk %d response: %+v", chunkIndex, resp)
-
-			return nil
-		})
-
-		if err != nil {
-			return nil, err
 		}
 	}
 
-	result.SuccessCount = len(result.Success)
-	result.FailedCount = len(result.Failed)
-
-	s.client.log(LoggerLevelInfo, "[object.create.recordsWithIterator] Create completed: total=%d, success=%d, failed=%d", result.Total, result.SuccessCount, result.FailedCount)
+	successCount := 0
+	failedCount := 0
+	for _, item := range page.Items {
+		if successVal, ok := item["success"]; ok {
+			if successBool, ok := successVal.(bool); ok && !successBool {
+				failedCount++
+			} else {
+				successCount++
+			}
+		} else {
+			successCount++
+		}
+	}
 
-	return result, nil
+	s.client.log(LoggerLevelInfo, "[object.create.recordsWithIterator] Chunk %d completed: %s, success=%d, failed=%d", chunkIndex, objectName, successCount, failedCount)
+	s.client.log(LoggerLevelTrace, "[object.create.recordsWithIterator] Chunk %d response: %+v", chunkIndex, resp)
+	return nil
 }
 
 // Record updates a single record.
 func (s *ObjectUpdateService) Record(ctx context.Context, params ObjectUpdateRecordParams) (*APIResponse, error) {
 	s.client.log(LoggerLevelInfo, "[object.update.record] Updating record: %s", params.RecordID)
 
-	var resp *APIResponse
-	err := s.client.limiter.Do(ctx, func() error {
-		if err := s.client.ensureTokenValid(ctx); err != nil {
-			return err
-		}
+	if err := s.client.preflight(ctx, params.ObjectName, []map[string]any{params.Record}, true); err != nil {
+		return nil, err
+	}
 
-		endpoint := fmt.Sprintf(
-			"/v1/data/namespaces/%s/objects/%s/records/%s",
-			url.PathEscape(s.client.namespace),
-			url.PathEscape(params.ObjectName),
-			url.PathEscape(params.RecordID),
-		)
-
-		payload := map[string]any{"record": params.Record}
-		var err error
-		resp, err = s.client.doJSON(ctx, http.MethodPatch, endpoint, payload, true, nil)
-		return err
-	})
+	if err := s.client.ensureTokenValid(ctx); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf(
+		"/v1/data/namespaces/%s/objects/%s/records/%s",
+		url.PathEscape(s.client.namespace),
+		url.PathEscape(params.ObjectName),
+		url.PathEscape(params.RecordID),
+	)
+
+	payload := map[string]any{"record": params.Record}
+	resp, err := s.client.doJSON(ctx, http.MethodPatch, endpoint, payload, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -612,23 +644,22 @@ func (s *ObjectUpdateService) Record(ctx context.Context, params ObjectUpdateRec
 func (s *ObjectUpdateService) Records(ctx context.Context, params ObjectUpdateRecordsParams) (*APIResponse, error) {
 	s.client.log(LoggerLevelInfo, "[object.update.records] Updating %d records", len(params.Records))
 
-	var resp *APIResponse
-	err := s.client.limiter.Do(ctx, func() error {
-		if err := s.client.ensureTokenValid(ctx); err != nil {
-			return err
-		}
+	if err := s.client.preflight(ctx, params.ObjectName, params.Records, true); err != nil {
+		return nil, err
+	}
+
+	if err := s.client.ensureTokenValid(ctx); err != nil {
+		return nil, err
+	}
 
-		endpoint := fmt.Sprintf(
-			"/v1/data/namespaces/%s/objects/%s/records_batch",
-			url.PathEscape(s.client.namespace),
-			url.PathEscape(params.ObjectName),
-		)
+	endpoint := fmt.Sprintf(
+		"/v1/data/namespaces/%s/objects/%s/records_batch",
+		url.PathEscape(s.client.namespace),
+		url.PathEscape(params.ObjectName),
+	)
 
-		payload := map[string]any{"records": params.Records}
-		var err error
-		resp, err = s.client.doJSON(ctx, http.MethodPatch, endpoint, payload, true, nil)
-		return err
-	})
+	payload := map[string]any{"records": params.Records}
+	resp, err := s.client.doJSON(ctx, http.MethodPatch, endpoint, payload, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -639,6 +670,8 @@ func (s *ObjectUpdateService) Records(ctx context.Context, params ObjectUpdateRe
 
 // RecordsWithIterator updates records in batches.
 func (s *ObjectUpdateService) RecordsWithIterator(ctx context.Context, params ObjectUpdateRecordsIteratorParams) (*BatchOperationResult, error) {
+	ctx = withBulkPriority(ctx)
+
 	total := len(params.Records)
 
 	// 参数校验
@@ -663,6 +696,15 @@ func (s *ObjectUpdateService) RecordsWithIterator(ctx context.Context, params Ob
 		Failed:  make([]OperationItem, 0),
 	}
 
+	// 预校验全部记录，避免部分批次已写入后才发现错误
+	if err := s.client.preflight(ctx, params.ObjectName, params.Records, true); err != nil {
+		return nil, err
+	}
+	ctx = withPreflightDone(ctx)
+
+	tracker := newProgressTracker(ctx, "object.update.recordsWithIterator")
+	tracker.setTotal(total, (total+chunkSize-1)/chunkSize)
+
 	s.client.log(LoggerLevelDebug, "[object.update.recordsWithIterator] Chunking %d records into groups of %d", total, chunkSize)
 
 	for index := 0; index < total; index += chunkSize {
@@ -675,166 +717,167 @@ func (s *ObjectUpdateService) RecordsWithIterator(ctx context.Context, params Ob
 
 		s.client.log(LoggerLevelDebug, "[object.update.recordsWithIterator] Processing chunk %d/%d: %d records", chunkIndex, (total+chunkSize-1)/chunkSize, len(chunk))
 
-		err := s.client.limiter.Do(ctx, func() error {
-			resp, err := s.Records(ctx, ObjectUpdateRecordsParams{
-				ObjectName: params.ObjectName,
-				Records:    chunk,
-			})
+		if err := s.updateChunk(ctx, params.ObjectName, chunk, chunkIndex, result); err != nil {
+			return nil, err
+		}
 
-			if err != nil {
-				s.client.log(LoggerLevelError, "[object.update.recordsWithIterator] Chunk %d threw error: %v", chunkIndex, err)
-				// 整个批次异常，将这批次的所有记录标记为失败
-				for _, record := range chunk {
-					id := "unknown"
-					if idVal, ok := record["_id"]; ok {
-						if idStr, ok := idVal.(string); ok {
-							id = idStr
-						}
-					}
-					result.Failed = append(result.Failed, OperationItem{
-						ID:      id,
-						Success: false,
-						Error:   err.Error(),
-					})
+		tracker.step(end, len(result.Success), len(result.Failed))
+	}
+
+	result.SuccessCount = len(result.Success)
+	result.FailedCount = len(result.Failed)
+	tracker.finish()
+
+	s.client.log(LoggerLevelInfo, "[object.update.recordsWithIterator] Update completed: total=%d, success=%d, failed=%d", result.Total, result.SuccessCount, result.FailedCount)
+
+	return result, nil
+}
+
+// updateChunk sends one chunk and records its outcome in result, see createChunk.
+func (s *ObjectUpdateService) updateChunk(ctx context.Context, objectName string, chunk []map[string]any, chunkIndex int, result *BatchOperationResult) error {
+	resp, err := s.Records(ctx, ObjectUpdateRecordsParams{
+		ObjectName: objectName,
+		Records:    chunk,
+	})
+
+	if err != nil {
+		if batchAborted(ctx, err) {
+			return err
+		}
+		s.client.log(LoggerLevelError, "[object.update.recordsWithIterator] Chunk %d threw error: %v", chunkIndex, err)
+		// 整个批次异常，将这批次的所有记录标记为失败
+		for _, record := range chunk {
+			id := "unknown"
+			if idVal, ok := record["_id"]; ok {
+				if idStr, ok := idVal.(string); ok {
+					id = idStr
 				}
-				return nil // 继续处理下一批次
 			}
+			result.Failed = append(result.Failed, OperationItem{
+				ID:      id,
+				Success: false,
+				Error:   err.Error(),
+			})
+		}
+		return nil // 继续处理下一批次
+	}
 
-			if resp.Code != "0" {
-				s.client.log(LoggerLevelError, "[object.update.recordsWithIterator] Chunk %d failed: code=%s, msg=%s", chunkIndex, resp.Code, resp.Msg)
-				// 整个批次失败，将这批次的所有记录标记为失败
-				for _, record := range chunk {
-					id := "unknown"
-					if idVal, ok := record["_id"]; ok {
-						if idStr, ok := idVal.(string); ok {
-							id = idStr
-						}
-					}
-					errMsg := resp.Msg
-					if errMsg == "" {
-						errMsg = fmt.Sprintf("Update failed with code %s", resp.Code)
-					}
-					result.Failed = append(result.Failed, OperationItem{
-						ID:      id,
-						Success: false,
-						Error:   errMsg,
-					})
+	if resp.Code != "0" {
+		s.client.log(LoggerLevelError, "[object.update.recordsWithIterator] Chunk %d failed: code=%s, msg=%s", chunkIndex, resp.Code, resp.Msg)
+		// 整个批次失败，将这批次的所有记录标记为失败
+		for _, record := range chunk {
+			id := "unknown"
+			if idVal, ok := record["_id"]; ok {
+				if idStr, ok := idVal.(string); ok {
+					id = idStr
 				}
-				return nil // 继续处理下一批次
 			}
-
-			// 处理响应中的 items
-			var page struct {
-				Items []map[string]any `json:"items"`
+			errMsg := resp.Msg
+			if errMsg == "" {
+				errMsg = fmt.Sprintf("Update failed with code %s", resp.Code)
 			}
-			if err := resp.DecodeData(&page); err != nil {
-				s.client.log(LoggerLevelError, "[object.update.recordsWithIterator] Failed to decode batch update response: %v", err)
-				for _, record := range chunk {
-					id := "unknown"
-					if idVal, ok := record["_id"]; ok {
-						if idStr, ok := idVal.(string); ok {
-							id = idStr
-						}
-					}
-					result.Failed = append(result.Failed, OperationItem{
-						ID:      id,
-						Success: false,
-						Error:   err.Error(),
-					})
+			result.Failed = append(result.Failed, OperationItem{
+				ID:      id,
+				Success: false,
+				Error:   errMsg,
+			})
+		}
+		return nil // 继续处理下一批次
+	}
+
+	// 处理响应中的 items
+	var page struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := resp.DecodeData(&page); err != nil {
+		s.client.log(LoggerLevelError, "[object.update.recordsWithIterator] Failed to decode batch update response: %v", err)
+		for _, record := range chunk {
+			id := "unknown"
+			if idVal, ok := record["_id"]; ok {
+				if idStr, ok := idVal.(string); ok {
+					id = idStr
 				}
-				return nil
 			}
+			result.Failed = append(result.Failed, OperationItem{
+				ID:      id,
+				Success: false,
+				Error:   err.Error(),
+			})
+		}
+		return nil
+	}
 
-			if len(page.Items) > 0 {
-				for _, item := range page.Items {
-					id := "unknown"
-					if idVal, ok := item["_id"]; ok {
-						if idStr, ok := idVal.(string); ok {
-							id = idStr
-						}
-					}
-
-					// Check success field
-					success := false
-					if successVal, ok := item["success"]; ok {
-						if successBool, ok := successVal.(bool); ok {
-							success = successBool
-						}
-					}
+	if len(page.Items) > 0 {
+		for _, item := range page.Items {
+			id := "unknown"
+			if idVal, ok := item["_id"]; ok {
+				if idStr, ok := idVal.(string); ok {
+					id = idStr
+				}
+			}
 
-					if success {
-						result.Success = append(result.Success, OperationItem{
-							ID:      id,
-							Success: true,
-						})
-					} else {
-						errMsg := ""
-						if errorVal, ok := item["error"]; ok {
-							if errorStr, ok := errorVal.(string); ok {
-								errMsg = errorStr
-							}
-						}
-						result.Failed = append(result.Failed, OperationItem{
-							ID:      id,
-							Success: false,
-							Error:   errMsg,
-						})
-					}
+			// Check success field
+			success := false
+			if successVal, ok := item["success"]; ok {
+				if successBool, ok := successVal.(bool); ok {
+					success = successBool
 				}
 			}
 
-			successCount := 0
-			failedCount := 0
-			for _, item := range page.Items {
-				if successVal, ok := item["success"]; ok {
-					if successBool, ok := successVal.(bool); ok && successBool {
-						successCount++
-					} else {
-						failedCount++
+			if success {
+				result.Success = append(result.Success, OperationItem{
+					ID:      id,
+					Success: true,
+				})
+			} else {
+				errMsg := ""
+				if errorVal, ok := item["error"]; ok {
+					if errorStr, ok := errorVal.(string); ok {
+						errMsg = errorStr
 					}
 				}
+				result.Failed = append(result.Failed, OperationItem{
+					ID:      id,
+					Success: false,
+					Error:   errMsg,
+				})
 			}
-
-			s.client.log(LoggerLevelDebug, "[object.update.recordsWithIterator] Chunk %d completed: %s, success=%d, failed=%d", chunkIndex, params.ObjectName, successCount, failedCount)
-			s.client.log(LoggerLevelTrace, "[object.update.recordsWithIterator] Chunk %d response: %+v", chunkIndex, resp)
-
-			return nil
-		})
-
-		if err != nil {
-			return nil, err
 		}
 	}
 
-	result.SuccessCount = len(result.Success)
-	result.FailedCount = len(result.Failed)
-
-	s.client.log(LoggerLevelInfo, "[object.update.recordsWithIterator] Update completed: total=%d, success=%d, failed=%d", result.Total, result.SuccessCount, result.FailedCount)
+	successCount := 0
+	failedCount := 0
+	for _, item := range page.Items {
+		if successVal, ok := item["success"]; ok {
+			if successBool, ok := successVal.(bool); ok && successBool {
+				successCount++
+			} else {
+				failedCount++
+			}
+		}
+	}
 
-	return result, nil
+	s.client.log(LoggerLevelDebug, "[object.update.recordsWithIterator] Chunk %d completed: %s, success=%d, failed=%d", chunkIndex, objectName, successCount, failedCount)
+	s.client.log(LoggerLevelTrace, "[object.update.recordsWithIterator] Chunk %d response: %+v", chunkIndex, resp)
+	return nil
 }
 
 // Record deletes a single record.
 func (s *ObjectDeleteService) Record(ctx context.Context, params ObjectDeleteRecordParams) (*APIResponse, error) {
 	s.client.log(LoggerLevelInfo, "[object.delete.record] Deleting record: %s.%s", params.ObjectName, params.RecordID)
 
-	var resp *APIResponse
-	err := s.client.limiter.Do(ctx, func() error {
-		if err := s.client.ensureTokenValid(ctx); err != nil {
-			return err
-		}
+	if err := s.client.ensureTokenValid(ctx); err != nil {
+		return nil, err
+	}
 
-		endpoint := fmt.Sprintf(
-			"/v1/data/namespaces/%s/objects/%s/records/%s",
-			url.PathEscape(s.client.namespace),
-			url.PathEscape(params.ObjectName),
-			url.PathEscape(params.RecordID),
-		)
+	endpoint := fmt.Sprintf(
+		"/v1/data/namespaces/%s/objects/%s/records/%s",
+		url.PathEscape(s.client.namespace),
+		url.PathEscape(params.ObjectName),
+		url.PathEscape(params.RecordID),
+	)
 
-		var err error
-		resp, err = s.client.doJSON(ctx, http.MethodDelete, endpoint, nil, true, nil)
-		return err
-	})
+	resp, err := s.client.doJSON(ctx, http.MethodDelete, endpoint, nil, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -867,6 +910,8 @@ func (s *ObjectDeleteService) Records(ctx context.Context, params ObjectDeleteRe
 
 // RecordsWithIterator deletes records in batches of 100.
 func (s *ObjectDeleteService) RecordsWithIterator(ctx context.Context, params ObjectDeleteRecordsIteratorParams) (*BatchOperationResult, error) {
+	ctx = withBulkPriority(ctx)
+
 	total := len(params.IDs)
 
 	// 参数校验
@@ -891,6 +936,9 @@ func (s *ObjectDeleteService) RecordsWithIterator(ctx context.Context, params Ob
 		Failed:  make([]OperationItem, 0),
 	}
 
+	tracker := newProgressTracker(ctx, "object.delete.recordsWithIterator")
+	tracker.setTotal(total, (total+chunkSize-1)/chunkSize)
+
 	s.client.log(LoggerLevelDebug, "[object.delete.recordsWithIterator] Chunking %d records into groups of %d", total, chunkSize)
 
 	for index := 0; index < total; index += chunkSize {
@@ -903,122 +951,128 @@ func (s *ObjectDeleteService) RecordsWithIterator(ctx context.Context, params Ob
 
 		s.client.log(LoggerLevelInfo, "[object.delete.recordsWithIterator] Processing chunk %d/%d: %d records", chunkIndex, (total+chunkSize-1)/chunkSize, len(chunk))
 
-		err := s.client.limiter.Do(ctx, func() error {
-			resp, err := s.Records(ctx, ObjectDeleteRecordsParams{
-				ObjectName: params.ObjectName,
-				IDs:        chunk,
+		if err := s.deleteChunk(ctx, params.ObjectName, chunk, chunkIndex, result); err != nil {
+			return nil, err
+		}
+
+		tracker.step(end, len(result.Success), len(result.Failed))
+	}
+
+	result.SuccessCount = len(result.Success)
+	result.FailedCount = len(result.Failed)
+	tracker.finish()
+
+	s.client.log(LoggerLevelInfo, "[object.delete.recordsWithIterator] Delete completed: total=%d, success=%d, failed=%d", result.Total, result.SuccessCount, result.FailedCount)
+
+	return result, nil
+}
+
+// deleteChunk sends one chunk and records its outcome in result, see createChunk.
+func (s *ObjectDeleteService) deleteChunk(ctx context.Context, objectName string, chunk []string, chunkIndex int, result *BatchOperationResult) error {
+	resp, err := s.Records(ctx, ObjectDeleteRecordsParams{
+		ObjectName: objectName,
+		IDs:        chunk,
+	})
+
+	if err != nil {
+		if batchAborted(ctx, err) {
+			return err
+		}
+		s.client.log(LoggerLevelError, "[object.delete.recordsWithIterator] Chunk %d threw error: %v", chunkIndex, err)
+		// 整个批次异常，将这批次的所有 ID 标记为失败
+		for _, id := range chunk {
+			result.Failed = append(result.Failed, OperationItem{
+				ID:      id,
+				Success: false,
+				Error:   err.Error(),
 			})
+		}
+		return nil // 继续处理下一批次
+	}
 
-			if err != nil {
-				s.client.log(LoggerLevelError, "[object.delete.recordsWithIterator] Chunk %d threw error: %v", chunkIndex, err)
-				// 整个批次异常，将这批次的所有 ID 标记为失败
-				for _, id := range chunk {
-					result.Failed = append(result.Failed, OperationItem{
-						ID:      id,
-						Success: false,
-						Error:   err.Error(),
-					})
-				}
-				return nil // 继续处理下一批次
+	if resp.Code != "0" {
+		s.client.log(LoggerLevelError, "[object.delete.recordsWithIterator] Chunk %d failed: code=%s, msg=%s", chunkIndex, resp.Code, resp.Msg)
+		// 整个批次失败，将这批次的所有 ID 标记为失败
+		for _, id := range chunk {
+			errMsg := resp.Msg
+			if errMsg == "" {
+				errMsg = fmt.Sprintf("Delete failed with code %s", resp.Code)
 			}
+			result.Failed = append(result.Failed, OperationItem{
+				ID:      id,
+				Success: false,
+				Error:   errMsg,
+			})
+		}
+		return nil // 继续处理下一批次
+	}
 
-			if resp.Code != "0" {
-				s.client.log(LoggerLevelError, "[object.delete.recordsWithIterator] Chunk %d failed: code=%s, msg=%s", chunkIndex, resp.Code, resp.Msg)
-				// 整个批次失败，将这批次的所有 ID 标记为失败
-				for _, id := range chunk {
-					errMsg := resp.Msg
-					if errMsg == "" {
-						errMsg = fmt.Sprintf("Delete failed with code %s", resp.Code)
-					}
-					result.Failed = append(result.Failed, OperationItem{
-						ID:      id,
-						Success: false,
-						Error:   errMsg,
-					})
-				}
-				return nil // 继续处理下一批次
-			}
+	// 处理响应中的 items
+	var page struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := resp.DecodeData(&page); err != nil {
+		s.client.log(LoggerLevelError, "[object.delete.recordsWithIterator] Failed to decode batch delete response: %v", err)
+		for _, id := range chunk {
+			result.Failed = append(result.Failed, OperationItem{
+				ID:      id,
+				Success: false,
+				Error:   err.Error(),
+			})
+		}
+		return nil
+	}
 
-			// 处理响应中的 items
-			var page struct {
-				Items []map[string]any `json:"items"`
-			}
-			if err := resp.DecodeData(&page); err != nil {
-				s.client.log(LoggerLevelError, "[object.delete.recordsWithIterator] Failed to decode batch delete response: %v", err)
-				for _, id := range chunk {
-					result.Failed = append(result.Failed, OperationItem{
-						ID:      id,
-						Success: false,
-						Error:   err.Error(),
-					})
+	if len(page.Items) > 0 {
+		for _, item := range page.Items {
+			id := "unknown"
+			if idVal, ok := item["_id"]; ok {
+				if idStr, ok := idVal.(string); ok {
+					id = idStr
 				}
-				return nil
 			}
 
-			if len(page.Items) > 0 {
-				for _, item := range page.Items {
-					id := "unknown"
-					if idVal, ok := item["_id"]; ok {
-						if idStr, ok := idVal.(string); ok {
-							id = idStr
-						}
-					}
-
-					// Check success field
-					success := false
-					if successVal, ok := item["success"]; ok {
-						if successBool, ok := successVal.(bool); ok {
-							success = successBool
-						}
-					}
-
-					if success {
-						result.Success = append(result.Success, OperationItem{
-							ID:      id,
-							Success: true,
-						})
-					} else {
-						errMsg := ""
-						if errorVal, ok := item["error"]; ok {
-							if errorStr, ok := errorVal.(string); ok {
-								errMsg = errorStr
-							}
-						}
-						result.Failed = append(result.Failed, OperationItem{
-							ID:      id,
-							Success: false,
-							Error:   errMsg,
-						})
-					}
+			// Check success field
+			success := false
+			if successVal, ok := item["success"]; ok {
+				if successBool, ok := successVal.(bool); ok {
+					success = successBool
 				}
 			}
 
-			successCount := 0
-			failedCount := 0
-			for _, item := range page.Items {
-				if successVal, ok := item["success"]; ok {
-					if successBool, ok := successVal.(bool); ok && successBool {
-						successCount++
-					} else {
-						failedCount++
+			if success {
+				result.Success = append(result.Success, OperationItem{
+					ID:      id,
+					Success: true,
+				})
+			} else {
+				errMsg := ""
+				if errorVal, ok := item["error"]; ok {
+					if errorStr, ok := errorVal.(string); ok {
+						errMsg = errorStr
 					}
 				}
+				result.Failed = append(result.Failed, OperationItem{
+					ID:      id,
+					Success: false,
+					Error:   errMsg,
+				})
 			}
-
-			s.client.log(LoggerLevelDebug, "[object.delete.recordsWithIterator] Chunk %d completed: %s, success=%d, failed=%d", chunkIndex, params.ObjectName, successCount, failedCount)
-
-			return nil
-		})
-
-		if err != nil {
-			return nil, err
 		}
 	}
 
-	result.SuccessCount = len(result.Success)
-	result.FailedCount = len(result.Failed)
-
-	s.client.log(LoggerLevelInfo, "[object.delete.recordsWithIterator] Delete completed: total=%d, success=%d, failed=%d", result.Total, result.SuccessCount, result.FailedCount)
+	successCount := 0
+	failedCount := 0
+	for _, item := range page.Items {
+		if successVal, ok := item["success"]; ok {
+			if successBool, ok := successVal.(bool); ok && successBool {
+				successCount++
+			} else {
+				failedCount++
+			}
+		}
+	}
 
-	return result, nil
+	s.client.log(LoggerLevelDebug, "[object.delete.recordsWithIterator] Chunk %d completed: %s, success=%d, failed=%d", chunkIndex, objectName, successCount, failedCount)
+	return nil
 }
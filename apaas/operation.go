@@ -0,0 +1,104 @@
+package apaas
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// Operation names an API call. The names match the log tags of the service methods.
+type Operation string
+
+// Operations issued by the client.
+const (
+	OpAuthToken             Operation = "auth.token"
+	OpObjectList            Operation = "object.list"
+	OpObjectMetadataField   Operation = "object.metadata.field"
+	OpObjectMetadataFields  Operation = "object.metadata.fields"
+	OpObjectSearchRecord    Operation = "object.search.record"
+	OpObjectSearchRecords   Operation = "object.search.records"
+	OpObjectCreateRecord    Operation = "object.create.record"
+	OpObjectCreateRecords   Operation = "object.create.records"
+	OpObjectUpdateRecord    Operation = "object.update.record"
+	OpObjectUpdateRecords   Operation = "object.update.records"
+	OpObjectDeleteRecord    Operation = "object.delete.record"
+	OpObjectDeleteRecords   Operation = "object.delete.records"
+	OpDepartmentExchange    Operation = "department.exchange"
+	OpFunctionInvoke        Operation = "function.invoke"
+	OpPageList              Operation = "page.list"
+	OpPageDetail            Operation = "page.detail"
+	OpPageURL               Operation = "page.url"
+	OpGlobalOptionsDetail   Operation = "global.options.detail"
+	OpGlobalOptionsList     Operation = "global.options.list"
+	OpGlobalVariablesDetail Operation = "global.variables.detail"
+	OpGlobalVariablesList   Operation = "global.variables.list"
+	OpAutomationV1Execute   Operation = "automation.v1.execute"
+	OpAutomationV2Execute   Operation = "automation.v2.execute"
+	OpAutomationV2Instance  Operation = "automation.v2.instance"
+	OpAttachmentDelete      Operation = "attachment.delete"
+	OpUnknown               Operation = "unknown"
+)
+
+// nonIdempotentOperations may have an effect every time they are sent.
+var nonIdempotentOperations = map[Operation]bool{
+	OpObjectCreateRecord:  true,
+	OpObjectCreateRecords: true,
+	OpFunctionInvoke:      true,
+	OpAutomationV1Execute: true,
+	OpAutomationV2Execute: true,
+}
+
+// Idempotent reports whether sending the operation twice has the same effect as
+// sending it once. Non-idempotent operations are retried automatically only when
+// the server rejected the request unprocessed (429, 503), with or without an
+// idempotency key; failures with an unknown outcome, such as timeouts or other 5xx
+// responses, are never retried because the platform does not promise to
+// deduplicate requests by Idempotency-Key.
+func (op Operation) Idempotent() bool {
+	return !nonIdempotentOperations[op]
+}
+
+type operationRoute struct {
+	method  string
+	pattern *regexp.Regexp
+	op      Operation
+}
+
+// operationRoutes maps request paths to operations; the first match wins.
+var operationRoutes = []operationRoute{
+	{http.MethodPost, regexp.MustCompile(`^/auth/v1/appToken$`), OpAuthToken},
+	{http.MethodPost, regexp.MustCompile(`^/api/data/v1/namespaces/[^/]+/meta/objects/list$`), OpObjectList},
+	{http.MethodGet, regexp.MustCompile(`^/api/data/v1/namespaces/[^/]+/meta/objects/[^/]+/fields/[^/]+$`), OpObjectMetadataField},
+	{http.MethodGet, regexp.MustCompile(`^/api/data/v1/namespaces/[^/]+/meta/objects/[^/]+$`), OpObjectMetadataFields},
+	{http.MethodPost, regexp.MustCompile(`^/v1/data/namespaces/[^/]+/objects/[^/]+/records_query$`), OpObjectSearchRecords},
+	{http.MethodPost, regexp.MustCompile(`^/v1/data/namespaces/[^/]+/objects/[^/]+/records_batch$`), OpObjectCreateRecords},
+	{http.MethodPost, regexp.MustCompile(`^/v1/data/namespaces/[^/]+/objects/[^/]+/records$`), OpObjectCreateRecord},
+	{http.MethodPost, regexp.MustCompile(`^/v1/data/namespaces/[^/]+/objects/[^/]+/records/[^/]+$`), OpObjectSearchRecord},
+	{http.MethodPatch, regexp.MustCompile(`^/v1/data/namespaces/[^/]+/objects/[^/]+/records_batch$`), OpObjectUpdateRecords},
+	{http.MethodPatch, regexp.MustCompile(`^/v1/data/namespaces/[^/]+/objects/[^/]+/records/[^/]+$`), OpObjectUpdateRecord},
+	{http.MethodDelete, regexp.MustCompile(`^/v1/data/namespaces/[^/]+/objects/[^/]+/records_batch$`), OpObjectDeleteRecords},
+	{http.MethodDelete, regexp.MustCompile(`^/v1/data/namespaces/[^/]+/objects/[^/]+/records/[^/]+$`), OpObjectDeleteRecord},
+	{http.MethodPost, regexp.MustCompile(`^/api/integration/v2/feishu/getDepartments$`), OpDepartmentExchange},
+	{http.MethodPost, regexp.MustCompile(`^/api/cloudfunction/v1/namespaces/[^/]+/invoke/[^/]+$`), OpFunctionInvoke},
+	{http.MethodPost, regexp.MustCompile(`^/api/builder/v1/namespaces/[^/]+/meta/pages$`), OpPageList},
+	{http.MethodGet, regexp.MustCompile(`^/api/builder/v1/namespaces/[^/]+/meta/pages/[^/]+$`), OpPageDetail},
+	{http.MethodPost, regexp.MustCompile(`^/api/builder/v1/namespaces/[^/]+/meta/pages/[^/]+/link$`), OpPageURL},
+	{http.MethodPost, regexp.MustCompile(`^/api/data/v1/namespaces/[^/]+/globalOptions/list$`), OpGlobalOptionsList},
+	{http.MethodGet, regexp.MustCompile(`^/api/data/v1/namespaces/[^/]+/globalOptions/[^/]+$`), OpGlobalOptionsDetail},
+	{http.MethodPost, regexp.MustCompile(`^/api/data/v1/namespaces/[^/]+/globalVariables/list$`), OpGlobalVariablesList},
+	{http.MethodGet, regexp.MustCompile(`^/api/data/v1/namespaces/[^/]+/globalVariables/[^/]+$`), OpGlobalVariablesDetail},
+	{http.MethodPost, regexp.MustCompile(`^/api/flow/v1/namespaces/[^/]+/flows/[^/]+/execute$`), OpAutomationV1Execute},
+	{http.MethodPost, regexp.MustCompile(`^/v2/namespaces/[^/]+/flows/[^/]+/execute$`), OpAutomationV2Execute},
+	{http.MethodGet, regexp.MustCompile(`^/v2/namespaces/[^/]+/flows/instances/[^/]+$`), OpAutomationV2Instance},
+	{http.MethodDelete, regexp.MustCompile(`^/v1/files/[^/]+$`), OpAttachmentDelete},
+}
+
+// operationFor identifies the operation of a request. Unknown requests are
+// treated as idempotent, like before operations were introduced.
+func operationFor(method, path string) Operation {
+	for _, route := range operationRoutes {
+		if route.method == method && route.pattern.MatchString(path) {
+			return route.op
+		}
+	}
+	return OpUnknown
+}
@@ -61,51 +61,33 @@ func (s *PageService) List(ctx context.Context, params PageListParams) (*APIResp
 
 // ListWithIterator retrieves all pages by auto-paginating.
 func (s *PageService) ListWithIterator(ctx context.Context, params *PageListWithIteratorParams) (*RecordsIteratorResult, error) {
-	limit := 100
-	if params != nil && params.Limit > 0 {
-		limit = params.Limit
+	opts := PaginatorOptions{}
+	if params != nil {
+		opts.PageSize = params.Limit
 	}
 
-	results := &RecordsIteratorResult{
-		Items: make([]map[string]any, 0),
+	paginator := s.listIterator(ctx, "page.listWithIterator", opts)
+	items, err := paginator.Collect()
+	if err != nil {
+		return nil, err
 	}
+	return &RecordsIteratorResult{Total: paginator.Total(), Items: items}, nil
+}
 
-	offset := 0
+// ListIterator lazily pages through all pages.
+func (s *PageService) ListIterator(ctx context.Context, opts PaginatorOptions) *Paginator[map[string]any] {
+	return s.listIterator(ctx, "page.listIterator", opts)
+}
 
-	for {
-		resp, err := s.List(ctx, PageListParams{
-			Limit:  limit,
-			Offset: offset,
-		})
+func (s *PageService) listIterator(ctx context.Context, operation string, opts PaginatorOptions) *Paginator[map[string]any] {
+	fetch := func(ctx context.Context, req PageRequest) (*Page, error) {
+		resp, err := s.List(ctx, PageListParams{Limit: req.Limit, Offset: req.Offset})
 		if err != nil {
 			return nil, err
 		}
-
-		var page struct {
-			Items []map[string]any `json:"items"`
-			Total int              `json:"total"`
-		}
-		if err := resp.DecodeData(&page); err != nil {
-			return nil, fmt.Errorf("failed to decode paginated pages: %w", err)
-		}
-
-		if results.Total == 0 && page.Total > 0 {
-			results.Total = page.Total
-		}
-
-		if len(page.Items) > 0 {
-			results.Items = append(results.Items, page.Items...)
-		}
-
-		s.client.log(LoggerLevelInfo, "[page.listWithIterator] Page completed: items=%d, offset=%d", len(page.Items), offset)
-
-		offset += limit
-		if len(results.Items) >= results.Total || len(page.Items) == 0 {
-			break
-		}
+		return decodePage(resp)
 	}
-
-	return results, nil
+	return newPaginator[map[string]any](ctx, s.client, operation, PaginationOffset, fetch, opts)
 }
 
 // Detail fetches metadata for a single page.
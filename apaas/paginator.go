@@ -0,0 +1,221 @@
+package apaas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// PaginationMode selects how a Paginator advances between pages.
+type PaginationMode int
+
+// Pagination modes.
+const (
+	// PaginationOffset advances by offset until Total items were read or a page is short.
+	PaginationOffset PaginationMode = iota
+	// PaginationToken follows NextPageToken until it is empty.
+	PaginationToken
+)
+
+// PageRequest identifies the page a PageFetcher should return.
+type PageRequest struct {
+	Offset    int
+	Limit     int
+	PageToken string
+}
+
+// Page is one page of raw items returned by a PageFetcher.
+type Page struct {
+	Items         []json.RawMessage `json:"items"`
+	Total         int               `json:"total"`
+	NextPageToken string            `json:"next_page_token"`
+}
+
+// PageFetcher loads a single page of a list endpoint.
+type PageFetcher func(ctx context.Context, req PageRequest) (*Page, error)
+
+// PaginatorOptions controls page size and the number of items returned.
+type PaginatorOptions struct {
+	PageSize int // 每页数量，默认 100
+	MaxItems int // 最多返回的数量，0 表示不限制
+}
+
+// Paginator lazily iterates over the items of a paginated endpoint, fetching the next
+// page only when the current one is consumed. Stop calling Next to end early.
+//
+//	p := client.Object.ListIterator(ctx, nil, apaas.PaginatorOptions{})
+//	for p.Next() {
+//		item := p.Item()
+//	}
+//	if err := p.Err(); err != nil { ... }
+type Paginator[T any] struct {
+	ctx       context.Context
+	client    *Client
+	operation string
+	mode      PaginationMode
+	fetch     PageFetcher
+	opts      PaginatorOptions
+	tracker   *progressTracker
+
+	buffer    []json.RawMessage
+	item      T
+	err       error
+	total     int
+	offset    int
+	token     string
+	fetched   int
+	count     int
+	exhausted bool
+	done      bool
+}
+
+// NewPaginator returns a Paginator over a custom endpoint.
+func NewPaginator[T any](ctx context.Context, mode PaginationMode, fetch PageFetcher, opts PaginatorOptions) *Paginator[T] {
+	return newPaginator[T](ctx, nil, "paginator", mode, fetch, opts)
+}
+
+func newPaginator[T any](ctx context.Context, client *Client, operation string, mode PaginationMode, fetch PageFetcher, opts PaginatorOptions) *Paginator[T] {
+	if opts.PageSize <= 0 {
+		opts.PageSize = 100
+	}
+	return &Paginator[T]{
+		ctx:       ctx,
+		client:    client,
+		operation: operation,
+		mode:      mode,
+		fetch:     fetch,
+		opts:      opts,
+		tracker:   newProgressTracker(ctx, operation),
+	}
+}
+
+// PaginatorAs returns an unstarted copy of p that decodes items into T.
+// It must be called before the first call to Next.
+func PaginatorAs[T, U any](p *Paginator[U]) *Paginator[T] {
+	return newPaginator[T](p.ctx, p.client, p.operation, p.mode, p.fetch, p.opts)
+}
+
+// Next advances to the next item, fetching a new page when needed. It returns false
+// when the items are exhausted, MaxItems is reached or an error occurred.
+func (p *Paginator[T]) Next() bool {
+	if p.err != nil || p.done {
+		return false
+	}
+	if p.opts.MaxItems > 0 && p.count >= p.opts.MaxItems {
+		p.finish()
+		return false
+	}
+
+	for len(p.buffer) == 0 {
+		if p.exhausted {
+			p.finish()
+			return false
+		}
+		if err := p.ctx.Err(); err != nil {
+			p.err = err
+			return false
+		}
+		if err := p.fetchPage(); err != nil {
+			p.err = err
+			return false
+		}
+	}
+
+	var item T
+	if err := json.Unmarshal(p.buffer[0], &item); err != nil {
+		p.err = fmt.Errorf("failed to decode item %d: %w", p.count, err)
+		return false
+	}
+	p.buffer = p.buffer[1:]
+	p.item = item
+	p.count++
+	return true
+}
+
+// Item returns the current item.
+func (p *Paginator[T]) Item() T {
+	return p.item
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (p *Paginator[T]) Err() error {
+	return p.err
+}
+
+// Total returns the total reported by the endpoint, or 0 when unknown.
+func (p *Paginator[T]) Total() int {
+	return p.total
+}
+
+// Collect reads all remaining items.
+func (p *Paginator[T]) Collect() ([]T, error) {
+	items := make([]T, 0)
+	for p.Next() {
+		items = append(items, p.item)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return items, nil
+}
+
+func (p *Paginator[T]) fetchPage() error {
+	req := PageRequest{Limit: p.opts.PageSize}
+	if p.mode == PaginationToken {
+		req.PageToken = p.token
+	} else {
+		req.Offset = p.offset
+	}
+
+	page, err := p.fetch(p.ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if page.Total > 0 {
+		p.total = page.Total
+	}
+	p.buffer = page.Items
+	p.fetched += len(page.Items)
+
+	if p.mode == PaginationToken {
+		p.token = page.NextPageToken
+		p.exhausted = page.NextPageToken == ""
+		p.log("[%s] Page completed: items=%d, next=%s", p.operation, len(page.Items), page.NextPageToken)
+	} else {
+		p.offset += len(page.Items)
+		p.exhausted = len(page.Items) == 0 ||
+			(p.total > 0 && p.offset >= p.total) ||
+			(p.total == 0 && len(page.Items) < req.Limit)
+		p.log("[%s] Page completed: items=%d, offset=%d", p.operation, len(page.Items), req.Offset)
+	}
+
+	p.tracker.setTotal(p.total, 0)
+	p.tracker.step(p.fetched, 0, 0)
+	return nil
+}
+
+func (p *Paginator[T]) finish() {
+	if !p.done {
+		p.done = true
+		p.tracker.finish()
+	}
+}
+
+func (p *Paginator[T]) log(format string, args ...any) {
+	if p.client != nil {
+		p.client.log(LoggerLevelInfo, format, args...)
+	}
+}
+
+// decodePage checks an API response and decodes its data as a Page.
+func decodePage(resp *APIResponse) (*Page, error) {
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+	var page Page
+	if err := resp.DecodeData(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode page: %w", err)
+	}
+	return &page, nil
+}
@@ -0,0 +1,103 @@
+package apaas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// offsetFetcher serves n items as {"n": i} and records the requests it received.
+func offsetFetcher(n int, withTotal bool, requests *[]PageRequest) PageFetcher {
+	return func(ctx context.Context, req PageRequest) (*Page, error) {
+		*requests = append(*requests, req)
+		page := &Page{}
+		if withTotal {
+			page.Total = n
+		}
+		for i := req.Offset; i < min(req.Offset+req.Limit, n); i++ {
+			page.Items = append(page.Items, json.RawMessage(fmt.Sprintf(`{"n":%d}`, i)))
+		}
+		return page, nil
+	}
+}
+
+type paginatorItem struct {
+	N int `json:"n"`
+}
+
+func TestPaginator_Offset(t *testing.T) {
+	tests := []struct {
+		name      string
+		n         int
+		withTotal bool
+		opts      PaginatorOptions
+		wantItems int
+		wantPages int
+	}{
+		{"with total", 5, true, PaginatorOptions{PageSize: 2}, 5, 3},
+		{"without total", 4, false, PaginatorOptions{PageSize: 2}, 4, 3},
+		{"max items stops early", 10, true, PaginatorOptions{PageSize: 2, MaxItems: 3}, 3, 2},
+		{"empty", 0, true, PaginatorOptions{}, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []PageRequest
+			items, err := NewPaginator[paginatorItem](context.Background(), PaginationOffset, offsetFetcher(tt.n, tt.withTotal, &requests), tt.opts).Collect()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(items) != tt.wantItems || len(requests) != tt.wantPages {
+				t.Errorf("items=%d pages=%d, want %d and %d", len(items), len(requests), tt.wantItems, tt.wantPages)
+			}
+			for i, item := range items {
+				if item.N != i {
+					t.Errorf("item %d = %d", i, item.N)
+				}
+			}
+		})
+	}
+}
+
+func TestPaginator_Token(t *testing.T) {
+	pages := map[string]*Page{
+		"":  {Items: []json.RawMessage{[]byte(`{"n":0}`)}, NextPageToken: "a"},
+		"a": {Items: []json.RawMessage{}, NextPageToken: "b"},
+		"b": {Items: []json.RawMessage{[]byte(`{"n":1}`)}},
+	}
+	var tokens []string
+	p := NewPaginator[paginatorItem](context.Background(), PaginationToken, func(ctx context.Context, req PageRequest) (*Page, error) {
+		tokens = append(tokens, req.PageToken)
+		return pages[req.PageToken], nil
+	}, PaginatorOptions{})
+
+	items, err := p.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[1].N != 1 || fmt.Sprint(tokens) != "[ a b]" {
+		t.Errorf("items=%+v tokens=%q", items, tokens)
+	}
+	if p.Next() {
+		t.Error("Next after exhaustion must return false")
+	}
+}
+
+func TestObjectListIterator_Typed(t *testing.T) {
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		body := decodeTestBody(t, r)
+		offset := int(body["offset"].(float64))
+		items := []any{map[string]any{"apiName": fmt.Sprintf("object_%d", offset)}}
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"items": items, "total": 2}})
+	})
+
+	objects, err := PaginatorAs[Object](client.Object.ListIterator(context.Background(), nil, PaginatorOptions{})).Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(objects) != 2 || objects[0].APIName != "object_0" || objects[1].APIName != "object_1" {
+		t.Errorf("unexpected objects: %+v", objects)
+	}
+}
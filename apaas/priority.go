@@ -0,0 +1,52 @@
+package apaas
+
+import "context"
+
+// Priority orders requests waiting for the rate limiter.
+type Priority int
+
+// Request priorities. Requests without a priority are PriorityNormal; bulk helpers
+// such as RecordsWithIterator and Scan default to PriorityLow.
+const (
+	PriorityLow Priority = iota - 1
+	PriorityNormal
+	PriorityHigh
+)
+
+// String returns the name of the priority.
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	}
+	return "unknown"
+}
+
+type priorityContextKey struct{}
+
+// WithPriority sets the priority of the requests made with ctx. When the limiter is
+// saturated, waiting requests are released in priority order.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, priority)
+}
+
+// PriorityFrom returns the priority set with WithPriority, PriorityNormal by default.
+// Custom Limiter implementations can use it to order waiting requests.
+func PriorityFrom(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityContextKey{}).(Priority); ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// withBulkPriority lowers the priority of bulk work unless the caller chose one.
+func withBulkPriority(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(priorityContextKey{}).(Priority); ok {
+		return ctx
+	}
+	return WithPriority(ctx, PriorityLow)
+}
@@ -0,0 +1,138 @@
+package apaas
+
+import (
+	"context"
+	"time"
+)
+
+// Progress is a snapshot of a long-running iterator, reported after every page or chunk.
+type Progress struct {
+	// Operation is the log tag of the iterator, e.g. "object.create.recordsWithIterator".
+	Operation string `json:"operation"`
+	// Steps is the number of pages or chunks completed; TotalSteps is 0 when unknown.
+	Steps      int `json:"steps"`
+	TotalSteps int `json:"totalSteps"`
+	// Processed is the number of items handled so far; Total is 0 when unknown.
+	Processed int `json:"processed"`
+	Total     int `json:"total"`
+	// Succeeded and Failed are only set by write iterators.
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Elapsed   time.Duration `json:"elapsed"`
+	// ETA is the estimated remaining time, 0 when Total is unknown.
+	ETA  time.Duration `json:"eta"`
+	Done bool          `json:"done"`
+}
+
+// Percent returns the completed share in [0, 100], or -1 when the total is unknown.
+func (p Progress) Percent() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+	return min(100, float64(p.Processed)*100/float64(p.Total))
+}
+
+// ProgressFunc receives progress updates. It is called synchronously from the
+// iterator and should return quickly.
+type ProgressFunc func(Progress)
+
+type progressKey struct{}
+
+// WithProgress returns a context that reports the progress of iterators
+// (RecordsWithIterator, ListWithIterator and the *ByQuery operations) called with it.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ProgressChan adapts a channel to a ProgressFunc. On a buffered channel updates
+// never block the iterator: when the channel is full the oldest pending update is
+// discarded, so the channel always holds the latest updates, including the final
+// Done one. On an unbuffered channel every send blocks the iterator until the
+// update is received; the consumer must keep reading until Done.
+func ProgressChan(ch chan Progress) ProgressFunc {
+	if cap(ch) == 0 {
+		return func(p Progress) {
+			ch <- p
+		}
+	}
+	return func(p Progress) {
+		for {
+			select {
+			case ch <- p:
+				return
+			default:
+			}
+			// 通道已满时丢弃最旧的一条，保证最新（含最终）的更新能送达
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	}
+}
+
+// withoutProgress hides the progress callback from nested iterators.
+func withoutProgress(ctx context.Context) context.Context {
+	if ctx.Value(progressKey{}) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, ProgressFunc(nil))
+}
+
+// progressTracker accumulates iterator state and forwards it to the context's ProgressFunc.
+// A tracker without callback is a no-op.
+type progressTracker struct {
+	fn    ProgressFunc
+	state Progress
+	start time.Time
+	now   func() time.Time
+}
+
+func newProgressTracker(ctx context.Context, operation string) *progressTracker {
+	fn, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	return &progressTracker{
+		fn:    fn,
+		state: Progress{Operation: operation},
+		start: time.Now(),
+		now:   time.Now,
+	}
+}
+
+// setTotal records the number of items and steps once they are known.
+func (t *progressTracker) setTotal(total, totalSteps int) {
+	if total > 0 {
+		t.state.Total = total
+	}
+	if totalSteps > 0 {
+		t.state.TotalSteps = totalSteps
+	}
+}
+
+// step reports a completed page or chunk with the running item counts.
+func (t *progressTracker) step(processed, succeeded, failed int) {
+	t.state.Steps++
+	t.state.Processed = processed
+	t.state.Succeeded = succeeded
+	t.state.Failed = failed
+	t.emit()
+}
+
+// finish reports the final state.
+func (t *progressTracker) finish() {
+	t.state.Done = true
+	t.emit()
+}
+
+func (t *progressTracker) emit() {
+	if t.fn == nil {
+		return
+	}
+
+	t.state.Elapsed = t.now().Sub(t.start)
+	t.state.ETA = 0
+	if !t.state.Done && t.state.Total > 0 && t.state.Processed > 0 && t.state.Processed < t.state.Total {
+		perItem := t.state.Elapsed / time.Duration(t.state.Processed)
+		t.state.ETA = perItem * time.Duration(t.state.Total-t.state.Processed)
+	}
+	t.fn(t.state)
+}
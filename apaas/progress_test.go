@@ -0,0 +1,112 @@
+package apaas
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestProgressTracker_ETA(t *testing.T) {
+	var got []Progress
+	tracker := newProgressTracker(WithProgress(context.Background(), func(p Progress) { got = append(got, p) }), "test")
+	start := time.Unix(0, 0)
+	tracker.start = start
+	tracker.now = func() time.Time { return start.Add(10 * time.Second) }
+
+	tracker.setTotal(100, 4)
+	tracker.step(25, 20, 5)
+	tracker.finish()
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 updates, got %d", len(got))
+	}
+	first := got[0]
+	if first.Steps != 1 || first.TotalSteps != 4 || first.Processed != 25 || first.Succeeded != 20 || first.Failed != 5 {
+		t.Errorf("unexpected progress: %+v", first)
+	}
+	if first.ETA != 30*time.Second || first.Percent() != 25 {
+		t.Errorf("ETA = %v, percent = %v, want 30s and 25", first.ETA, first.Percent())
+	}
+	if !got[1].Done || got[1].ETA != 0 {
+		t.Errorf("unexpected final progress: %+v", got[1])
+	}
+}
+
+func TestProgressChan_KeepsLatestWhenFull(t *testing.T) {
+	ch := make(chan Progress, 2)
+	fn := ProgressChan(ch)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 1; i <= 4; i++ {
+			fn(Progress{Steps: i, Done: i == 4})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ProgressChan blocked on a full channel")
+	}
+
+	if p := <-ch; p.Steps != 3 {
+		t.Errorf("expected the oldest updates to be dropped, got %+v", p)
+	}
+	if p := <-ch; p.Steps != 4 || !p.Done {
+		t.Errorf("expected the final update to be delivered, got %+v", p)
+	}
+}
+
+func TestProgressChan_UnbufferedBlocks(t *testing.T) {
+	ch := make(chan Progress)
+	fn := ProgressChan(ch)
+
+	go fn(Progress{Steps: 1, Done: true})
+	select {
+	case p := <-ch:
+		if !p.Done {
+			t.Errorf("unexpected update: %+v", p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the update on the unbuffered channel")
+	}
+}
+
+func TestRecordsWithIterator_ReportsProgress(t *testing.T) {
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		records := decodeTestBody(t, r)["records"].([]any)
+		items := make([]any, len(records))
+		for i := range records {
+			items[i] = map[string]any{"_id": "x", "success": i == 0}
+		}
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"items": items}})
+	})
+
+	var got []Progress
+	ctx := WithProgress(context.Background(), func(p Progress) { got = append(got, p) })
+	_, err := client.Object.Create.RecordsWithIterator(ctx, ObjectCreateRecordsIteratorParams{
+		ObjectName: "object_store",
+		Records:    []map[string]any{{"a": 1}, {"a": 2}, {"a": 3}},
+		Limit:      2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Progress{
+		{Steps: 1, TotalSteps: 2, Processed: 2, Total: 3, Succeeded: 1, Failed: 1},
+		{Steps: 2, TotalSteps: 2, Processed: 3, Total: 3, Succeeded: 2, Failed: 1},
+		{Steps: 2, TotalSteps: 2, Processed: 3, Total: 3, Succeeded: 2, Failed: 1, Done: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d updates, got %+v", len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Operation != "object.create.recordsWithIterator" || g.Steps != w.Steps || g.TotalSteps != w.TotalSteps ||
+			g.Processed != w.Processed || g.Total != w.Total || g.Succeeded != w.Succeeded || g.Failed != w.Failed || g.Done != w.Done {
+			t.Errorf("update %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
@@ -2,8 +2,12 @@ package apaas
 
 import (
 	"context"
+	"errors"
 	"math"
 	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,6 +23,23 @@ type RetryConfig struct {
 	Multiplier float64
 	// Jitter enables random jitter to prevent thundering herd
 	Jitter bool
+	// RetryIf decides whether an error is retried; IsRetryableError is used when nil
+	RetryIf func(err error) bool
+	// MaxElapsedTime bounds the total time spent on all attempts and delays; zero means no limit
+	MaxElapsedTime time.Duration
+	// IgnoreRetryAfter disables waiting for the Retry-After header of 429 and 503 responses
+	IgnoreRetryAfter bool
+	// OnRetry is called before each retry
+	OnRetry func(RetryAttempt)
+}
+
+// RetryAttempt describes a failed attempt that is about to be retried.
+type RetryAttempt struct {
+	Operation Operation     // 请求对应的操作，直接调用 Retry 时为空
+	Attempt   int           // 失败的尝试次数，从 1 开始
+	Err       error         // 本次失败的错误
+	Delay     time.Duration // 下次重试前的等待时间
+	Elapsed   time.Duration // 从第一次尝试开始经过的时间
 }
 
 // DefaultRetryConfig returns the default retry configuration.
@@ -35,9 +56,27 @@ func DefaultRetryConfig() RetryConfig {
 // RetryableFunc is a function that can be retried.
 type RetryableFunc func() error
 
+type retryConfigContextKey struct{}
+
+// WithRetryConfig overrides the retry policy of the requests made with ctx, taking
+// precedence over ClientOptions.RetryConfig and ClientOptions.RetryPolicies.
+func WithRetryConfig(ctx context.Context, config RetryConfig) context.Context {
+	return context.WithValue(ctx, retryConfigContextKey{}, config)
+}
+
 // Retry executes a function with exponential backoff retry logic.
 func Retry(ctx context.Context, config RetryConfig, fn RetryableFunc) error {
+	return retry(ctx, config, "", fn)
+}
+
+func retry(ctx context.Context, config RetryConfig, op Operation, fn RetryableFunc) error {
 	var lastErr error
+	start := time.Now()
+
+	retryable := IsRetryableError
+	if config.RetryIf != nil {
+		retryable = config.RetryIf
+	}
 
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
 		// Execute the function
@@ -49,7 +88,7 @@ func Retry(ctx context.Context, config RetryConfig, fn RetryableFunc) error {
 		lastErr = err
 
 		// Check if the error is retryable
-		if !IsRetryableError(err) {
+		if !retryable(err) {
 			return err
 		}
 
@@ -60,12 +99,30 @@ func Retry(ctx context.Context, config RetryConfig, fn RetryableFunc) error {
 
 		// Calculate backoff delay
 		delay := calculateBackoff(attempt, config)
+		if !config.IgnoreRetryAfter {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+				delay = apiErr.RetryAfter
+			}
+		}
+
+		// Stop when the next attempt would start after the time budget
+		elapsed := time.Since(start)
+		if config.MaxElapsedTime > 0 && elapsed+delay > config.MaxElapsedTime {
+			break
+		}
+
+		if config.OnRetry != nil {
+			config.OnRetry(RetryAttempt{Operation: op, Attempt: attempt + 1, Err: err, Delay: delay, Elapsed: elapsed})
+		}
 
 		// Check if context is canceled
+		timer := time.NewTimer(delay)
 		select {
 		case <-ctx.Done():
+			timer.Stop()
 			return ctx.Err()
-		case <-time.After(delay):
+		case <-timer.C:
 			// Continue to next retry
 		}
 	}
@@ -92,3 +149,41 @@ func calculateBackoff(attempt int, config RetryConfig) time.Duration {
 
 	return time.Duration(delay)
 }
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// retryConfigFor resolves the retry policy of op: the context override, then the
+// policy registered for the longest matching operation prefix, then the client default.
+func (c *Client) retryConfigFor(ctx context.Context, op Operation) RetryConfig {
+	if config, ok := ctx.Value(retryConfigContextKey{}).(RetryConfig); ok {
+		return config
+	}
+
+	name := string(op)
+	for {
+		if config, ok := c.retryPolicies[name]; ok {
+			return config
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			return c.retryConfig
+		}
+		name = name[:i]
+	}
+}
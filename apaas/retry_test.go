@@ -3,6 +3,8 @@ package apaas
 import (
 	"context"
 	"errors"
+	"net/http"
+	"sync"
 	"testing"
 	"time"
 )
@@ -163,3 +165,130 @@ func TestCalculateBackoff_WithJitter(t *testing.T) {
 		}
 	}
 }
+
+func TestRetry_RetryIf(t *testing.T) {
+	config := RetryConfig{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+	config.RetryIf = func(err error) bool { return StatusCode(err) == 409 }
+
+	attempts := 0
+	err := Retry(context.Background(), config, func() error {
+		attempts++
+		if attempts == 1 {
+			return &APIError{StatusCode: 409}
+		}
+		return &APIError{StatusCode: 500}
+	})
+
+	if StatusCode(err) != 500 {
+		t.Errorf("expected the 500 error, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestRetry_RetryAfterAndOnRetry(t *testing.T) {
+	var events []RetryAttempt
+	config := RetryConfig{
+		MaxRetries:   1,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+		Multiplier:   1,
+		OnRetry:      func(a RetryAttempt) { events = append(events, a) },
+	}
+
+	attempts := 0
+	start := time.Now()
+	err := Retry(context.Background(), config, func() error {
+		attempts++
+		if attempts == 1 {
+			return &APIError{StatusCode: 429, RetryAfter: 30 * time.Millisecond}
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected Retry-After to be honored, retried after %v", elapsed)
+	}
+	if len(events) != 1 || events[0].Attempt != 1 || events[0].Delay != 30*time.Millisecond {
+		t.Errorf("unexpected retry events: %+v", events)
+	}
+}
+
+func TestRetry_MaxElapsedTime(t *testing.T) {
+	config := RetryConfig{
+		MaxRetries:     10,
+		InitialDelay:   20 * time.Millisecond,
+		MaxDelay:       20 * time.Millisecond,
+		Multiplier:     1,
+		MaxElapsedTime: 50 * time.Millisecond,
+	}
+
+	attempts := 0
+	err := Retry(context.Background(), config, func() error {
+		attempts++
+		return &APIError{StatusCode: 503}
+	})
+
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts within the budget, got %d", attempts)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{now.Add(5 * time.Second).Format(http.TimeFormat), 5 * time.Second},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestClient_RetryPolicies(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	client := newTestClient(t, ClientOptions{
+		RetryConfig: &RetryConfig{MaxRetries: 2, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1},
+		RetryPolicies: map[string]RetryConfig{
+			"object.search": {MaxRetries: 0},
+		},
+	}, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls[r.URL.Path]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	ctx := context.Background()
+
+	client.Object.Search.Records(ctx, ObjectSearchRecordsParams{ObjectName: "order"})
+	if n := calls["/v1/data/namespaces/app_test/objects/order/records_query"]; n != 1 {
+		t.Errorf("expected the object.search policy to disable retries, got %d calls", n)
+	}
+
+	client.Object.Update.Record(ctx, ObjectUpdateRecordParams{ObjectName: "order", RecordID: "1", Record: map[string]any{"a": 1}})
+	if n := calls["/v1/data/namespaces/app_test/objects/order/records/1"]; n != 3 {
+		t.Errorf("expected the default policy, got %d calls", n)
+	}
+
+	override := WithRetryConfig(ctx, RetryConfig{MaxRetries: 1, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1})
+	client.Object.Search.Records(override, ObjectSearchRecordsParams{ObjectName: "order"})
+	if n := calls["/v1/data/namespaces/app_test/objects/order/records_query"]; n != 3 {
+		t.Errorf("expected the context policy to retry once, got %d calls for the request", n-1)
+	}
+}
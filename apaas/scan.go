@@ -0,0 +1,241 @@
+package apaas
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ObjectScanParams scans a whole object with several partitions in parallel.
+type ObjectScanParams struct {
+	ObjectName string
+	Data       map[string]any // records_query 参数，例如 select；不能包含 filter
+	Filter     *RecordFilter  // 可选，与分区条件组合
+	// PartitionField is the ordered field the object is split on, "_id" by default.
+	// It must never change once a record exists (_id, _createdAt); other fields are
+	// rejected unless ImmutablePartitionField confirms they are write-once.
+	PartitionField string
+	// ImmutablePartitionField declares that a custom PartitionField is never updated.
+	// A record whose value changes during the scan can move to a range that was
+	// already read and be missed.
+	ImmutablePartitionField bool
+	// Boundaries are strictly increasing split points of PartitionField. n boundaries
+	// produce the n+1 half-open ranges (-∞, b0), [b0, b1), …, [bn-1, +∞). See EvenBoundaries.
+	Boundaries  []any
+	Concurrency int // 并发分区数，默认 4
+	PageSize    int // 每页数量，默认 100
+}
+
+// ObjectScanResult summarizes a partitioned scan.
+type ObjectScanResult struct {
+	Records    int   `json:"records"`
+	Partitions []int `json:"partitions"` // 每个分区读取到的记录数
+}
+
+type scanPartition struct {
+	index  int
+	filter *RecordFilter
+}
+
+// Scan reads all matching records by splitting the object into disjoint ranges of
+// PartitionField and scanning them concurrently. handle receives the merged stream
+// from a single goroutine, in no particular order. Because the partition field is
+// immutable, every record belongs to exactly one range and is delivered once; Scan
+// keeps no per-record state, so memory does not grow with the size of the object.
+//
+// The ranges cover every value of PartitionField; for fields other than _id an extra
+// partition reads records whose field is empty. A handle error stops the scan.
+func (s *ObjectSearchService) Scan(ctx context.Context, params ObjectScanParams, handle func(record map[string]any) error) (*ObjectScanResult, error) {
+	ctx = withBulkPriority(ctx)
+
+	if handle == nil {
+		return nil, fmt.Errorf("handle function is required")
+	}
+	if _, ok := params.Data["filter"]; ok {
+		return nil, fmt.Errorf("data.filter is not supported by Scan, use Filter")
+	}
+
+	partitions, err := scanPartitions(params)
+	if err != nil {
+		return nil, err
+	}
+	concurrency := params.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tracker := newProgressTracker(ctx, "object.search.scan")
+	tracker.setTotal(0, len(partitions))
+	innerCtx := withoutProgress(ctx)
+
+	s.client.log(LoggerLevelInfo, "[object.search.scan] Scanning %s: partitions=%d, concurrency=%d", params.ObjectName, len(partitions), concurrency)
+
+	type scanRecord struct {
+		partition int
+		record    map[string]any
+	}
+	records := make(chan scanRecord, concurrency*2)
+	finished := make(chan int, len(partitions))
+	queue := make(chan scanPartition, len(partitions))
+	for _, partition := range partitions {
+		queue <- partition
+	}
+	close(queue)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		fetchErr error
+	)
+	for worker := 0; worker < min(concurrency, len(partitions)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partition := range queue {
+				paginator := s.RecordsIterator(innerCtx, ObjectRecordsIteratorParams{
+					ObjectName: params.ObjectName,
+					Data:       params.Data,
+					Filter:     params.Filter.And(partition.filter),
+				}, PaginatorOptions{PageSize: params.PageSize})
+
+				for paginator.Next() {
+					select {
+					case records <- scanRecord{partition: partition.index, record: paginator.Item()}:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if err := paginator.Err(); err != nil {
+					errOnce.Do(func() {
+						fetchErr = fmt.Errorf("partition %d failed: %w", partition.index, err)
+						cancel()
+					})
+					return
+				}
+				finished <- partition.index
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(records)
+	}()
+
+	result := &ObjectScanResult{Partitions: make([]int, len(partitions))}
+	var handleErr error
+	for item := range records {
+		if handleErr != nil {
+			continue // 等待 worker 退出
+		}
+
+		result.Partitions[item.partition]++
+		result.Records++
+		if err := handle(item.record); err != nil {
+			handleErr = err
+			cancel()
+		}
+
+		for drained := false; !drained; {
+			select {
+			case <-finished:
+				tracker.step(result.Records, 0, 0)
+			default:
+				drained = true
+			}
+		}
+	}
+
+	if handleErr != nil {
+		return nil, handleErr
+	}
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for len(finished) > 0 {
+		<-finished
+		tracker.step(result.Records, 0, 0)
+	}
+	tracker.finish()
+
+	s.client.log(LoggerLevelInfo, "[object.search.scan] Scan completed: %s, records=%d", params.ObjectName, result.Records)
+	return result, nil
+}
+
+// immutableFields are the system fields that never change after a record is created.
+var immutableFields = map[string]bool{"_id": true, "_createdAt": true}
+
+// scanPartitions builds one range filter per partition.
+func scanPartitions(params ObjectScanParams) ([]scanPartition, error) {
+	field := params.PartitionField
+	if field == "" {
+		field = "_id"
+	}
+	if !immutableFields[field] && !params.ImmutablePartitionField {
+		return nil, fmt.Errorf("partition field %s may change during the scan; use _id or _createdAt, or set ImmutablePartitionField", field)
+	}
+	for i := 1; i < len(params.Boundaries); i++ {
+		prev, okPrev := toFloat(params.Boundaries[i-1])
+		next, okNext := toFloat(params.Boundaries[i])
+		if okPrev && okNext && prev >= next {
+			return nil, fmt.Errorf("boundaries must be strictly increasing: %v >= %v", params.Boundaries[i-1], params.Boundaries[i])
+		}
+		if !okPrev || !okNext {
+			if fmt.Sprint(params.Boundaries[i-1]) >= fmt.Sprint(params.Boundaries[i]) {
+				return nil, fmt.Errorf("boundaries must be strictly increasing: %v >= %v", params.Boundaries[i-1], params.Boundaries[i])
+			}
+		}
+	}
+
+	partitions := make([]scanPartition, 0, len(params.Boundaries)+2)
+	add := func(conditions ...FilterCondition) {
+		var filter *RecordFilter
+		if len(conditions) > 0 {
+			filter = NewRecordFilter(conditions...)
+		}
+		partitions = append(partitions, scanPartition{index: len(partitions), filter: filter})
+	}
+
+	if len(params.Boundaries) == 0 {
+		add()
+		return partitions, nil
+	}
+
+	add(Where(field, FilterLess, params.Boundaries[0]))
+	for i := 1; i < len(params.Boundaries); i++ {
+		add(Where(field, FilterGreaterEq, params.Boundaries[i-1]), Where(field, FilterLess, params.Boundaries[i]))
+	}
+	add(Where(field, FilterGreaterEq, params.Boundaries[len(params.Boundaries)-1]))
+	if field != "_id" {
+		add(Where(field, FilterIsEmpty, nil))
+	}
+	return partitions, nil
+}
+
+// EvenBoundaries splits [lower, upper] into partitions ranges of equal width, e.g. for
+// _id or millisecond timestamps, and returns the partitions-1 split points.
+func EvenBoundaries(lower, upper int64, partitions int) []any {
+	if partitions <= 1 || upper <= lower {
+		return nil
+	}
+	width := (upper - lower) / int64(partitions)
+	if width == 0 {
+		width = 1
+	}
+
+	boundaries := make([]any, 0, partitions-1)
+	for i := 1; i < partitions; i++ {
+		point := lower + width*int64(i)
+		if point > upper {
+			break
+		}
+		boundaries = append(boundaries, point)
+	}
+	return boundaries
+}
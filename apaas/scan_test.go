@@ -0,0 +1,140 @@
+package apaas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// scanTestServer serves records with _id 1..n from records_query, honoring gte/lt
+// conditions on _id and using the page token as offset.
+func scanTestServer(t *testing.T, n int, inflight, peak *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(inflight, 1)
+		defer atomic.AddInt32(inflight, -1)
+		for {
+			old := atomic.LoadInt32(peak)
+			if current <= old || atomic.CompareAndSwapInt32(peak, old, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond) // 让并发请求有机会重叠
+
+		body := decodeTestBody(t, r)
+		lower, upper := 1.0, float64(n+1)
+		if filter, ok := body["filter"].(map[string]any); ok {
+			for _, raw := range filter["conditions"].([]any) {
+				condition := raw.(map[string]any)
+				var right struct {
+					Data float64 `json:"data"`
+				}
+				settings := condition["right"].(map[string]any)["settings"].(string)
+				if err := json.Unmarshal([]byte(settings), &right); err != nil {
+					t.Errorf("invalid right settings: %v", err)
+				}
+				switch condition["operator"] {
+				case "gte":
+					lower = max(lower, right.Data)
+				case "lt":
+					upper = min(upper, right.Data)
+				}
+			}
+		}
+
+		offset, _ := strconv.Atoi(body["page_token"].(string))
+		pageSize := int(body["page_size"].(float64))
+		items := make([]any, 0)
+		id := int(lower) + offset
+		for ; id < int(upper) && len(items) < pageSize; id++ {
+			items = append(items, map[string]any{"_id": id})
+		}
+		next := ""
+		if id < int(upper) {
+			next = strconv.Itoa(offset + len(items))
+		}
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"items": items, "next_page_token": next}})
+	}
+}
+
+func TestObjectSearchScan(t *testing.T) {
+	var inflight, peak int32
+	client := newTestClient(t, ClientOptions{}, scanTestServer(t, 250, &inflight, &peak))
+
+	seen := make(map[string]int)
+	result, err := client.Object.Search.Scan(context.Background(), ObjectScanParams{
+		ObjectName:  "object_store",
+		Boundaries:  EvenBoundaries(1, 250, 4),
+		Concurrency: 4,
+		PageSize:    20,
+	}, func(record map[string]any) error {
+		seen[recordID(record)]++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Records != 250 || len(seen) != 250 || len(result.Partitions) != 4 {
+		t.Fatalf("unexpected result: %+v, seen=%d", result, len(seen))
+	}
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("record %s delivered %d times", id, count)
+		}
+	}
+	for i, count := range result.Partitions {
+		if count == 0 {
+			t.Errorf("partition %d is empty", i)
+		}
+	}
+	if atomic.LoadInt32(&peak) < 2 {
+		t.Errorf("expected concurrent requests, peak=%d", peak)
+	}
+}
+
+func TestObjectSearchScan_HandleError(t *testing.T) {
+	var inflight, peak int32
+	client := newTestClient(t, ClientOptions{}, scanTestServer(t, 500, &inflight, &peak))
+
+	stop := errors.New("stop")
+	count := 0
+	_, err := client.Object.Search.Scan(context.Background(), ObjectScanParams{
+		ObjectName: "object_store",
+		Boundaries: EvenBoundaries(1, 500, 3),
+	}, func(record map[string]any) error {
+		if count++; count == 10 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || count != 10 {
+		t.Errorf("expected handle error after 10 records, got %v after %d", err, count)
+	}
+}
+
+func TestScanPartitions(t *testing.T) {
+	partitions, err := scanPartitions(ObjectScanParams{PartitionField: "_createdAt", Boundaries: []any{10, 20}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// (<10), [10,20), (>=20), empty
+	if len(partitions) != 4 || partitions[3].filter.Conditions[0].Operator != FilterIsEmpty {
+		t.Errorf("unexpected partitions: %+v", partitions)
+	}
+
+	if _, err := scanPartitions(ObjectScanParams{Boundaries: []any{20, 10}}); err == nil {
+		t.Error("expected error for decreasing boundaries")
+	}
+
+	if _, err := scanPartitions(ObjectScanParams{PartitionField: "status", Boundaries: []any{10}}); err == nil {
+		t.Error("expected error for a mutable partition field")
+	}
+	if _, err := scanPartitions(ObjectScanParams{PartitionField: "order_no", ImmutablePartitionField: true, Boundaries: []any{10}}); err != nil {
+		t.Errorf("unexpected error for a declared immutable field: %v", err)
+	}
+}
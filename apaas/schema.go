@@ -0,0 +1,394 @@
+package apaas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaSnapshot captures object, field and global option metadata of a namespace.
+type SchemaSnapshot struct {
+	Namespace     string               `json:"namespace"`
+	CapturedAt    time.Time            `json:"capturedAt"`
+	Objects       []ObjectSchema       `json:"objects"`
+	GlobalOptions []GlobalOptionSchema `json:"globalOptions,omitempty"`
+}
+
+// ObjectSchema describes an object and its fields.
+type ObjectSchema struct {
+	APIName string        `json:"apiName"`
+	Fields  []FieldSchema `json:"fields"`
+}
+
+// FieldSchema holds the comparable attributes of a field.
+type FieldSchema struct {
+	APIName      string   `json:"apiName"`
+	Type         string   `json:"type"`
+	Required     bool     `json:"required"`
+	Options      []string `json:"options,omitempty"`
+	GlobalOption string   `json:"globalOption,omitempty"`
+}
+
+// GlobalOptionSchema holds the option values of a global option set.
+type GlobalOptionSchema struct {
+	APIName string   `json:"apiName"`
+	Options []string `json:"options"`
+}
+
+// SchemaCaptureOptions narrows what CaptureSchema collects.
+type SchemaCaptureOptions struct {
+	// Objects restricts the capture to the given object API names. Empty means all objects.
+	Objects []string
+	// ObjectType filters the object listing, e.g. "custom".
+	ObjectType string
+	// IncludeGlobalOptions also captures global option sets.
+	IncludeGlobalOptions bool
+}
+
+// SchemaChangeKind classifies a schema difference.
+type SchemaChangeKind string
+
+// Schema change kinds reported by DiffSchemas.
+const (
+	SchemaObjectAdded          SchemaChangeKind = "object_added"
+	SchemaObjectRemoved        SchemaChangeKind = "object_removed"
+	SchemaFieldAdded           SchemaChangeKind = "field_added"
+	SchemaFieldRemoved         SchemaChangeKind = "field_removed"
+	SchemaFieldTypeChanged     SchemaChangeKind = "field_type_changed"
+	SchemaFieldRequiredChanged SchemaChangeKind = "field_required_changed"
+	SchemaFieldOptionsChanged  SchemaChangeKind = "field_options_changed"
+	SchemaGlobalOptionAdded    SchemaChangeKind = "global_option_added"
+	SchemaGlobalOptionRemoved  SchemaChangeKind = "global_option_removed"
+	SchemaGlobalOptionChanged  SchemaChangeKind = "global_option_changed"
+)
+
+// SchemaChange is a single difference between two snapshots.
+type SchemaChange struct {
+	Kind         SchemaChangeKind `json:"kind"`
+	Object       string           `json:"object,omitempty"`
+	Field        string           `json:"field,omitempty"`
+	GlobalOption string           `json:"globalOption,omitempty"`
+	From         any              `json:"from,omitempty"`
+	To           any              `json:"to,omitempty"`
+}
+
+// SchemaDiff lists the differences from one snapshot to another.
+type SchemaDiff struct {
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Changes []SchemaChange `json:"changes"`
+}
+
+// HasChanges reports whether the snapshots differ.
+func (d *SchemaDiff) HasChanges() bool {
+	return d != nil && len(d.Changes) > 0
+}
+
+// String renders the diff as a human readable report.
+func (d *SchemaDiff) String() string {
+	if d == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "schema diff %s -> %s: %d change(s)\n", d.From, d.To, len(d.Changes))
+	for _, change := range d.Changes {
+		target := change.Object
+		if change.Field != "" {
+			target += "." + change.Field
+		}
+		if change.GlobalOption != "" {
+			target = change.GlobalOption
+		}
+
+		if change.From != nil || change.To != nil {
+			fmt.Fprintf(&b, "  %s %s: %s -> %s\n", change.Kind, target, mustJSON(change.From), mustJSON(change.To))
+		} else {
+			fmt.Fprintf(&b, "  %s %s\n", change.Kind, target)
+		}
+	}
+	return b.String()
+}
+
+// Save writes the snapshot as indented JSON.
+func (s *SchemaSnapshot) Save(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return fmt.Errorf("failed to encode schema snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSchemaSnapshot reads a snapshot previously written by Save.
+func LoadSchemaSnapshot(r io.Reader) (*SchemaSnapshot, error) {
+	var snapshot SchemaSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode schema snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// CaptureSchema reads object, field and optionally global option metadata from the client's namespace.
+func CaptureSchema(ctx context.Context, client *Client, opts SchemaCaptureOptions) (*SchemaSnapshot, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+
+	snapshot := &SchemaSnapshot{
+		Namespace:  client.namespace,
+		CapturedAt: time.Now().UTC(),
+		Objects:    make([]ObjectSchema, 0),
+	}
+
+	objectNames := opts.Objects
+	if len(objectNames) == 0 {
+		names, err := listObjectNames(ctx, client, opts.ObjectType)
+		if err != nil {
+			return nil, err
+		}
+		objectNames = names
+	}
+
+	for _, name := range objectNames {
+		meta, err := client.Object.Metadata.FieldsTyped(ctx, ObjectMetadataFieldsParams{ObjectName: name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch fields of %s: %w", name, err)
+		}
+
+		object := ObjectSchema{APIName: name, Fields: make([]FieldSchema, 0, len(meta.Fields))}
+		for _, field := range meta.Fields {
+			object.Fields = append(object.Fields, fieldSchema(field))
+		}
+		sort.Slice(object.Fields, func(i, j int) bool { return object.Fields[i].APIName < object.Fields[j].APIName })
+		snapshot.Objects = append(snapshot.Objects, object)
+
+		client.log(LoggerLevelDebug, "[schema.capture] Object captured: %s, fields=%d", name, len(object.Fields))
+	}
+	sort.Slice(snapshot.Objects, func(i, j int) bool { return snapshot.Objects[i].APIName < snapshot.Objects[j].APIName })
+
+	if opts.IncludeGlobalOptions {
+		options, err := captureGlobalOptions(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.GlobalOptions = options
+	}
+
+	client.log(LoggerLevelInfo, "[schema.capture] Schema captured: objects=%d, globalOptions=%d", len(snapshot.Objects), len(snapshot.GlobalOptions))
+	return snapshot, nil
+}
+
+// DiffNamespaces captures both namespaces and diffs them.
+func DiffNamespaces(ctx context.Context, from, to *Client, opts SchemaCaptureOptions) (*SchemaDiff, error) {
+	left, err := CaptureSchema(ctx, from, opts)
+	if err != nil {
+		return nil, err
+	}
+	right, err := CaptureSchema(ctx, to, opts)
+	if err != nil {
+		return nil, err
+	}
+	return DiffSchemas(left, right), nil
+}
+
+// DiffSchemas compares two snapshots and reports the changes needed to go from "from" to "to".
+func DiffSchemas(from, to *SchemaSnapshot) *SchemaDiff {
+	if from == nil {
+		from = &SchemaSnapshot{}
+	}
+	if to == nil {
+		to = &SchemaSnapshot{}
+	}
+
+	diff := &SchemaDiff{From: from.Namespace, To: to.Namespace, Changes: make([]SchemaChange, 0)}
+
+	fromObjects := make(map[string]ObjectSchema, len(from.Objects))
+	for _, object := range from.Objects {
+		fromObjects[object.APIName] = object
+	}
+	toObjects := make(map[string]ObjectSchema, len(to.Objects))
+	for _, object := range to.Objects {
+		toObjects[object.APIName] = object
+	}
+
+	for _, name := range sortedKeys(fromObjects, toObjects) {
+		left, inFrom := fromObjects[name]
+		right, inTo := toObjects[name]
+		switch {
+		case !inTo:
+			diff.Changes = append(diff.Changes, SchemaChange{Kind: SchemaObjectRemoved, Object: name})
+		case !inFrom:
+			diff.Changes = append(diff.Changes, SchemaChange{Kind: SchemaObjectAdded, Object: name})
+		default:
+			diff.Changes = append(diff.Changes, diffFields(name, left.Fields, right.Fields)...)
+		}
+	}
+
+	fromOptions := make(map[string]GlobalOptionSchema, len(from.GlobalOptions))
+	for _, option := range from.GlobalOptions {
+		fromOptions[option.APIName] = option
+	}
+	toOptions := make(map[string]GlobalOptionSchema, len(to.GlobalOptions))
+	for _, option := range to.GlobalOptions {
+		toOptions[option.APIName] = option
+	}
+
+	for _, name := range sortedKeys(fromOptions, toOptions) {
+		left, inFrom := fromOptions[name]
+		right, inTo := toOptions[name]
+		switch {
+		case !inTo:
+			diff.Changes = append(diff.Changes, SchemaChange{Kind: SchemaGlobalOptionRemoved, GlobalOption: name})
+		case !inFrom:
+			diff.Changes = append(diff.Changes, SchemaChange{Kind: SchemaGlobalOptionAdded, GlobalOption: name})
+		case !equalStringSets(left.Options, right.Options):
+			diff.Changes = append(diff.Changes, SchemaChange{
+				Kind:         SchemaGlobalOptionChanged,
+				GlobalOption: name,
+				From:         left.Options,
+				To:           right.Options,
+			})
+		}
+	}
+
+	return diff
+}
+
+func diffFields(object string, from, to []FieldSchema) []SchemaChange {
+	changes := make([]SchemaChange, 0)
+
+	fromFields := make(map[string]FieldSchema, len(from))
+	for _, field := range from {
+		fromFields[field.APIName] = field
+	}
+	toFields := make(map[string]FieldSchema, len(to))
+	for _, field := range to {
+		toFields[field.APIName] = field
+	}
+
+	for _, name := range sortedKeys(fromFields, toFields) {
+		left, inFrom := fromFields[name]
+		right, inTo := toFields[name]
+		switch {
+		case !inTo:
+			changes = append(changes, SchemaChange{Kind: SchemaFieldRemoved, Object: object, Field: name})
+			continue
+		case !inFrom:
+			changes = append(changes, SchemaChange{Kind: SchemaFieldAdded, Object: object, Field: name, To: right.Type})
+			continue
+		}
+
+		if left.Type != right.Type {
+			changes = append(changes, SchemaChange{Kind: SchemaFieldTypeChanged, Object: object, Field: name, From: left.Type, To: right.Type})
+		}
+		if left.Required != right.Required {
+			changes = append(changes, SchemaChange{Kind: SchemaFieldRequiredChanged, Object: object, Field: name, From: left.Required, To: right.Required})
+		}
+		if left.GlobalOption != right.GlobalOption || !equalStringSets(left.Options, right.Options) {
+			changes = append(changes, SchemaChange{Kind: SchemaFieldOptionsChanged, Object: object, Field: name, From: left.Options, To: right.Options})
+		}
+	}
+
+	return changes
+}
+
+func listObjectNames(ctx context.Context, client *Client, objectType string) ([]string, error) {
+	var filter *ObjectListFilter
+	if objectType != "" {
+		filter = &ObjectListFilter{Type: objectType}
+	}
+
+	objects, err := PaginatorAs[Object](client.Object.ListIterator(ctx, filter, PaginatorOptions{})).Collect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	names := make([]string, 0, len(objects))
+	for _, object := range objects {
+		names = append(names, object.APIName)
+	}
+	return names, nil
+}
+
+func captureGlobalOptions(ctx context.Context, client *Client) ([]GlobalOptionSchema, error) {
+	list, err := client.Global.Options.ListWithIterator(ctx, 100, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list global options: %w", err)
+	}
+
+	options := make([]GlobalOptionSchema, 0, len(list.Items))
+	for _, item := range list.Items {
+		name, _ := item["apiName"].(string)
+		if name == "" {
+			continue
+		}
+
+		set, err := client.Global.Options.DetailTyped(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch global option %s: %w", name, err)
+		}
+
+		options = append(options, GlobalOptionSchema{APIName: name, Options: sortedOptionNames(set)})
+	}
+	sort.Slice(options, func(i, j int) bool { return options[i].APIName < options[j].APIName })
+	return options, nil
+}
+
+func fieldSchema(field Field) FieldSchema {
+	schema := FieldSchema{
+		APIName:  field.APIName,
+		Type:     string(field.Type),
+		Required: field.Required,
+	}
+	if field.Options != nil {
+		schema.Options = sortedOptionNames(field.Options)
+		schema.GlobalOption = field.Options.GlobalOptionAPIName
+	}
+	return schema
+}
+
+func sortedOptionNames(set *OptionSet) []string {
+	names := set.Names()
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return names
+}
+
+func equalStringSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	left := append([]string(nil), a...)
+	right := append([]string(nil), b...)
+	sort.Strings(left)
+	sort.Strings(right)
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys[V any](maps ...map[string]V) []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
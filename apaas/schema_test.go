@@ -0,0 +1,112 @@
+package apaas
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	staging := &SchemaSnapshot{
+		Namespace: "staging",
+		Objects: []ObjectSchema{
+			{APIName: "object_store", Fields: []FieldSchema{
+				{APIName: "name", Type: "text", Required: true},
+				{APIName: "status", Type: "option", Options: []string{"open", "closed", "paused"}},
+				{APIName: "score", Type: "number"},
+				{APIName: "manager", Type: "lookup"},
+			}},
+			{APIName: "object_new", Fields: []FieldSchema{{APIName: "_id", Type: "bigint"}}},
+		},
+		GlobalOptions: []GlobalOptionSchema{
+			{APIName: "region", Options: []string{"north", "south"}},
+		},
+	}
+	prod := &SchemaSnapshot{
+		Namespace: "prod",
+		Objects: []ObjectSchema{
+			{APIName: "object_store", Fields: []FieldSchema{
+				{APIName: "name", Type: "text", Required: false},
+				{APIName: "status", Type: "option", Options: []string{"closed", "open"}},
+				{APIName: "score", Type: "text"},
+				{APIName: "legacy", Type: "text"},
+			}},
+		},
+		GlobalOptions: []GlobalOptionSchema{
+			{APIName: "region", Options: []string{"south", "north"}},
+			{APIName: "tier", Options: []string{"gold"}},
+		},
+	}
+
+	diff := DiffSchemas(staging, prod)
+
+	want := map[SchemaChangeKind]int{
+		SchemaObjectRemoved:        1,
+		SchemaFieldAdded:           1,
+		SchemaFieldRemoved:         1,
+		SchemaFieldTypeChanged:     1,
+		SchemaFieldRequiredChanged: 1,
+		SchemaFieldOptionsChanged:  1,
+		SchemaGlobalOptionAdded:    1,
+	}
+	got := make(map[SchemaChangeKind]int)
+	for _, change := range diff.Changes {
+		got[change.Kind]++
+	}
+	for kind, count := range want {
+		if got[kind] != count {
+			t.Errorf("expected %d %s change(s), got %d (%v)", count, kind, got[kind], diff.Changes)
+		}
+	}
+	if got[SchemaGlobalOptionChanged] != 0 {
+		t.Error("option order must not be reported as a change")
+	}
+	if len(diff.Changes) != len(want) {
+		t.Errorf("expected %d changes, got %d: %s", len(want), len(diff.Changes), diff)
+	}
+}
+
+func TestDiffSchemas_Identical(t *testing.T) {
+	snapshot := &SchemaSnapshot{
+		Objects: []ObjectSchema{{APIName: "object_store", Fields: []FieldSchema{{APIName: "name", Type: "text"}}}},
+	}
+	if diff := DiffSchemas(snapshot, snapshot); diff.HasChanges() {
+		t.Errorf("expected no changes, got %s", diff)
+	}
+}
+
+func TestSchemaSnapshot_SaveLoad(t *testing.T) {
+	snapshot := &SchemaSnapshot{
+		Namespace: "app_test",
+		Objects:   []ObjectSchema{{APIName: "object_store", Fields: []FieldSchema{{APIName: "status", Type: "option", Options: []string{"open"}}}}},
+	}
+
+	var buf bytes.Buffer
+	if err := snapshot.Save(&buf); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	loaded, err := LoadSchemaSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if diff := DiffSchemas(snapshot, loaded); diff.HasChanges() {
+		t.Errorf("round trip changed the snapshot: %s", diff)
+	}
+}
+
+func TestFieldSchema(t *testing.T) {
+	raw := `{"apiName":"status","type":{"name":"option","settings":{"required":true,"options":[{"apiName":"b"},{"apiName":"a"}]}}}`
+
+	var field Field
+	if err := json.Unmarshal([]byte(raw), &field); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+
+	schema := fieldSchema(field)
+	if schema.Type != "option" || !schema.Required {
+		t.Errorf("unexpected schema: %+v", schema)
+	}
+	if len(schema.Options) != 2 || schema.Options[0] != "a" {
+		t.Errorf("expected sorted options, got %v", schema.Options)
+	}
+}
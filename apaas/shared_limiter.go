@@ -0,0 +1,258 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LimiterStore holds the state of shared limiters. Implementations backed by an
+// external store (Redis, a database) let processes on different hosts share a budget.
+type LimiterStore interface {
+	// Update atomically replaces the value of key with fn's result. A missing key has
+	// the value 0. fn may be called more than once when the store retries on conflicts.
+	Update(ctx context.Context, key string, fn func(value int64) (int64, error)) error
+}
+
+// SharedLimiterOptions configures a SharedLimiter.
+type SharedLimiterOptions struct {
+	Store LimiterStore
+	// Key identifies the budget, e.g. the client ID plus the limiter bucket. Every
+	// process using the same store and key shares the same budget.
+	Key                 string
+	RequestsPerInterval int
+	Interval            time.Duration
+	Burst               int
+}
+
+// SharedLimiter enforces one request budget across every process sharing its store.
+// It schedules requests with the generic cell rate algorithm, which needs a single
+// timestamp per key: each request reserves the next slot and sleeps until it is due.
+type SharedLimiter struct {
+	opts     SharedLimiterOptions
+	emission time.Duration
+	now      func() time.Time
+}
+
+// NewSharedLimiter returns a limiter allowing RequestsPerInterval requests per Interval
+// with bursts of Burst requests in total for all its users.
+func NewSharedLimiter(opts SharedLimiterOptions) (*SharedLimiter, error) {
+	if opts.Store == nil {
+		return nil, fmt.Errorf("limiter store is required")
+	}
+	if strings.TrimSpace(opts.Key) == "" {
+		return nil, fmt.Errorf("limiter key is required")
+	}
+	defaults := DefaultLimiterOptions()
+	if opts.RequestsPerInterval <= 0 {
+		opts.RequestsPerInterval = defaults.RequestsPerInterval
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaults.Interval
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = 1
+	}
+	return &SharedLimiter{
+		opts:     opts,
+		emission: opts.Interval / time.Duration(opts.RequestsPerInterval),
+		now:      time.Now,
+	}, nil
+}
+
+// Wait implements Limiter. The reserved slot is not returned when ctx ends while waiting.
+func (l *SharedLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var delay time.Duration
+	err := l.opts.Store.Update(ctx, l.opts.Key, func(tat int64) (int64, error) {
+		now := l.now().UnixNano()
+		if tat < now {
+			tat = now
+		}
+		next := tat + int64(l.emission)
+		delay = time.Duration(next - now - int64(l.emission)*int64(l.opts.Burst))
+		return next, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reserve shared limiter slot: %w", err)
+	}
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// MemoryLimiterStore keeps limiter state in memory. It shares a budget between
+// clients of one process and serves as a fake store in tests.
+type MemoryLimiterStore struct {
+	mu     sync.Mutex
+	values map[string]int64
+}
+
+// NewMemoryLimiterStore returns an empty in-memory store.
+func NewMemoryLimiterStore() *MemoryLimiterStore {
+	return &MemoryLimiterStore{values: make(map[string]int64)}
+}
+
+// Update implements LimiterStore.
+func (s *MemoryLimiterStore) Update(ctx context.Context, key string, fn func(value int64) (int64, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, err := fn(s.values[key])
+	if err != nil {
+		return err
+	}
+	s.values[key] = value
+	return nil
+}
+
+// FileLimiterStore keeps each key in a file in Dir, serialized with a file lock, so
+// that processes on one host (or sharing a file system with working locks) share a budget.
+type FileLimiterStore struct {
+	Dir string
+	// LockTimeout bounds the wait for the file lock, 5 seconds by default. The lock
+	// is only held while one value is read and written, so a longer wait means the
+	// holder is stuck.
+	LockTimeout time.Duration
+}
+
+// defaultLockTimeout is the default FileLimiterStore.LockTimeout.
+const defaultLockTimeout = 5 * time.Second
+
+// Update implements LimiterStore.
+func (s FileLimiterStore) Update(ctx context.Context, key string, fn func(value int64) (int64, error)) error {
+	file, err := os.OpenFile(filepath.Join(s.Dir, url.PathEscape(key)+".limiter"), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	timeout := s.LockTimeout
+	if timeout <= 0 {
+		timeout = defaultLockTimeout
+	}
+	unlock, err := lockFile(ctx, file, timeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	var value int64
+	if text := strings.TrimSpace(string(data)); text != "" {
+		if value, err = strconv.ParseInt(text, 10, 64); err != nil {
+			return fmt.Errorf("invalid limiter state %s: %w", key, err)
+		}
+	}
+
+	value, err = fn(value)
+	if err != nil {
+		return err
+	}
+
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := file.WriteAt([]byte(strconv.FormatInt(value, 10)), 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+// errLockBusy is returned by tryLockFile when another process holds the lock.
+var errLockBusy = errors.New("file is locked")
+
+// lockFile takes an exclusive lock on file, polling until ctx is done or timeout
+// has passed.
+func lockFile(ctx context.Context, file *os.File, timeout time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		unlock, err := tryLockFile(file)
+		if !errors.Is(err, errLockBusy) {
+			return unlock, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: gave up after %s waiting for %s", errLockBusy, timeout, file.Name())
+		}
+		timer := time.NewTimer(time.Millisecond)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// staleLockAge is how long a lock file may exist before it is treated as left
+// behind by a crashed process.
+const staleLockAge = 10 * time.Second
+
+// tryCreateLockFile takes a lock by creating name exclusively, with the owner PID as
+// its content. A lock file whose owner has exited, or older than staleAfter, is
+// removed so that the next attempt can take the lock.
+func tryCreateLockFile(name string, staleAfter time.Duration) (func(), error) {
+	lock, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		removeStaleLock(name, staleAfter)
+		return nil, errLockBusy
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, err = lock.WriteString(strconv.Itoa(os.Getpid()))
+	if closeErr := lock.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(name)
+		return nil, err
+	}
+	return func() {
+		_ = os.Remove(name)
+	}, nil
+}
+
+func removeStaleLock(name string, staleAfter time.Duration) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return
+	}
+	stale := time.Since(info.ModTime()) > staleAfter
+	if !stale {
+		// 持有者写入 PID 之前文件为空，此时只能按修改时间判断
+		data, err := os.ReadFile(name)
+		if pid, parseErr := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && parseErr == nil {
+			stale = !processAlive(pid)
+		}
+	}
+	if !stale {
+		return
+	}
+	// 删除前确认锁文件没有被其他进程重新创建
+	if current, err := os.Stat(name); err == nil && os.SameFile(info, current) {
+		_ = os.Remove(name)
+	}
+}
@@ -0,0 +1,191 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSharedLimiterSharesBudget(t *testing.T) {
+	store := NewMemoryLimiterStore()
+	newLimiter := func() *SharedLimiter {
+		limiter, err := NewSharedLimiter(SharedLimiterOptions{
+			Store:               store,
+			Key:                 "tenant/records.write",
+			RequestsPerInterval: 20,
+			Interval:            time.Second,
+			Burst:               2,
+		})
+		if err != nil {
+			t.Fatalf("failed to create limiter: %v", err)
+		}
+		return limiter
+	}
+	// 模拟两个进程共用同一个预算
+	a, b := newLimiter(), newLimiter()
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := a.Wait(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := b.Wait(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// 突发 2 个请求后每 50ms 放行一个
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("expected 4 requests to take at least 100ms in total, took %v", elapsed)
+	}
+}
+
+func TestSharedLimiterContextCanceled(t *testing.T) {
+	limiter, err := NewSharedLimiter(SharedLimiterOptions{
+		Store:               NewMemoryLimiterStore(),
+		Key:                 "tenant",
+		RequestsPerInterval: 1,
+		Interval:            time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("failed to create limiter: %v", err)
+	}
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestFileLimiterStoreSerializesUpdates(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store := FileLimiterStore{Dir: dir}
+			if err := store.Update(ctx, "tenant/flow", func(value int64) (int64, error) {
+				return value + 1, nil
+			}); err != nil {
+				t.Errorf("update failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var final int64
+	FileLimiterStore{Dir: dir}.Update(ctx, "tenant/flow", func(value int64) (int64, error) {
+		final = value
+		return value, nil
+	})
+	if final != 20 {
+		t.Fatalf("expected 20 serialized updates, got %d", final)
+	}
+}
+
+func TestFileLimiterStoreLockTimeout(t *testing.T) {
+	dir := t.TempDir()
+	file, err := os.OpenFile(filepath.Join(dir, url.PathEscape("tenant/flow")+".limiter"), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		t.Fatalf("failed to open limiter file: %v", err)
+	}
+	defer file.Close()
+	unlock, err := tryLockFile(file)
+	if err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+	defer unlock()
+
+	store := FileLimiterStore{Dir: dir, LockTimeout: 30 * time.Millisecond}
+	err = store.Update(context.Background(), "tenant/flow", func(value int64) (int64, error) {
+		return value + 1, nil
+	})
+	if !errors.Is(err, errLockBusy) {
+		t.Fatalf("expected the wait for a held lock to give up, got %v", err)
+	}
+}
+
+func TestCreateLockFileRemovesStaleLocks(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "state.lock")
+
+	exited := exec.Command(os.Args[0], "-test.run=^$")
+	if err := exited.Run(); err != nil {
+		t.Fatalf("failed to run helper process: %v", err)
+	}
+	os.WriteFile(name, []byte(strconv.Itoa(exited.Process.Pid)), 0o644)
+	if _, err := tryCreateLockFile(name, time.Minute); !errors.Is(err, errLockBusy) {
+		t.Fatalf("expected the lock to be busy, got %v", err)
+	}
+	unlock, err := tryCreateLockFile(name, time.Minute)
+	if err != nil {
+		t.Fatalf("expected the lock of an exited process to be removed, got %v", err)
+	}
+	unlock()
+
+	// 持有者仍存活但锁文件超时
+	os.WriteFile(name, []byte(strconv.Itoa(os.Getpid())), 0o644)
+	tryCreateLockFile(name, time.Minute)
+	if _, err := os.Stat(name); err != nil {
+		t.Fatalf("expected a fresh lock of a live process to be kept, got %v", err)
+	}
+	old := time.Now().Add(-2 * time.Minute)
+	os.Chtimes(name, old, old)
+	tryCreateLockFile(name, time.Minute)
+	unlock, err = tryCreateLockFile(name, time.Minute)
+	if err != nil {
+		t.Fatalf("expected an expired lock to be removed, got %v", err)
+	}
+	unlock()
+}
+
+type countingLimiter struct {
+	waits atomic.Int32
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.waits.Add(1)
+	return nil
+}
+
+func TestClientUsesCustomLimiter(t *testing.T) {
+	limiter := &countingLimiter{}
+	flowLimiter := &countingLimiter{}
+	client := newTestClient(t, ClientOptions{
+		Limiter:        limiter,
+		BucketLimiters: map[string]Limiter{LimiterBucketFlow: flowLimiter},
+	}, func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{}})
+	})
+	ctx := context.Background()
+
+	if _, err := client.Page.Detail(ctx, PageDetailParams{PageID: "page_1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Automation.V2.Execute(ctx, AutomationV2ExecuteParams{FlowAPIName: "approve"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// token 请求与页面请求使用默认限流器
+	if limiter.waits.Load() != 2 || flowLimiter.waits.Load() != 1 {
+		t.Fatalf("unexpected waits: default=%d flow=%d", limiter.waits.Load(), flowLimiter.waits.Load())
+	}
+	if client.RateLimiter() != nil {
+		t.Fatalf("expected no built-in rate limiter")
+	}
+}
@@ -0,0 +1,209 @@
+package apaas
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FunctionOptions configures a typed cloud function.
+type FunctionOptions struct {
+	// Timeout bounds each invocation, including retries; zero means no limit
+	// beyond the caller's context and the HTTP client timeout.
+	Timeout time.Duration
+	// ErrorCodes are the business codes the function returns for its own errors;
+	// an entry ending in "*" matches codes with that prefix, e.g. "k_cf_*". Only
+	// these codes become *FunctionError, other codes stay *APIError.
+	ErrorCodes []string
+}
+
+// isErrorCode reports whether code is one of the function's own error codes.
+func (o FunctionOptions) isErrorCode(code string) bool {
+	for _, c := range o.ErrorCodes {
+		if prefix, ok := strings.CutSuffix(c, "*"); ok {
+			if strings.HasPrefix(code, prefix) {
+				return true
+			}
+		} else if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// TypedFunction invokes a cloud function with In as its params and decodes the
+// function's result into Out. Declare it once with DefineFunction or RegisterFunction
+// and reuse it; it is safe for concurrent use.
+type TypedFunction[In, Out any] struct {
+	name string
+	opts FunctionOptions
+}
+
+// DefineFunction declares a typed cloud function.
+func DefineFunction[In, Out any](name string, opts FunctionOptions) *TypedFunction[In, Out] {
+	return &TypedFunction[In, Out]{name: name, opts: opts}
+}
+
+// Name returns the API name of the function.
+func (f *TypedFunction[In, Out]) Name() string {
+	return f.name
+}
+
+// Invoke executes the function. Codes listed in FunctionOptions.ErrorCodes are
+// returned as *FunctionError; any other code, such as authentication, permission,
+// missing function or throttling, is an *APIError, and transport failures keep
+// their usual *NetworkError type.
+func (f *TypedFunction[In, Out]) Invoke(ctx context.Context, client *Client, input In) (Out, error) {
+	var out Out
+	if client == nil {
+		return out, fmt.Errorf("client is required")
+	}
+	if strings.TrimSpace(f.name) == "" {
+		return out, fmt.Errorf("function name is required")
+	}
+
+	if f.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.opts.Timeout)
+		defer cancel()
+	}
+
+	resp, err := client.Function.invoke(ctx, f.name, input)
+	if err != nil {
+		return out, err
+	}
+	return f.decode(client, resp)
+}
+
+func (f *TypedFunction[In, Out]) decode(client *Client, resp *APIResponse) (Out, error) {
+	var out Out
+	if err := client.checkFunctionResponse(f.name, f.opts, resp); err != nil {
+		return out, err
+	}
+	if err := resp.DecodeData(&out); err != nil {
+		return out, fmt.Errorf("failed to decode result of cloud function %s: %w", f.name, err)
+	}
+	return out, nil
+}
+
+// checkFunctionResponse separates the function's own error codes from platform
+// business codes; throttling codes are never function errors.
+func (c *Client) checkFunctionResponse(name string, opts FunctionOptions, resp *APIResponse) error {
+	if resp == nil || resp.Code == "0" || resp.Code == "" {
+		return checkResponse(resp)
+	}
+	if !opts.isErrorCode(resp.Code) || isRateLimitCode(c.limiterFor(OpFunctionInvoke), resp.Code) {
+		return checkResponse(resp)
+	}
+	return &FunctionError{Name: name, Code: resp.Code, Message: resp.Msg}
+}
+
+// FunctionSignature describes a function declared in a FunctionRegistry.
+type FunctionSignature struct {
+	Name       string
+	Input      reflect.Type
+	Output     reflect.Type
+	Timeout    time.Duration
+	ErrorCodes []string
+}
+
+// FunctionRegistry declares the cloud functions of an application in one place, so
+// that every caller invokes a function with the same types and options.
+type FunctionRegistry struct {
+	mu        sync.RWMutex
+	functions map[string]registeredFunction
+}
+
+type registeredFunction struct {
+	signature FunctionSignature
+	function  any
+}
+
+// NewFunctionRegistry returns an empty registry.
+func NewFunctionRegistry() *FunctionRegistry {
+	return &FunctionRegistry{functions: make(map[string]registeredFunction)}
+}
+
+// RegisterFunction declares a typed function in r. Registering the same name again
+// returns the existing function when the types and options match, and an error otherwise.
+func RegisterFunction[In, Out any](r *FunctionRegistry, name string, opts FunctionOptions) (*TypedFunction[In, Out], error) {
+	if r == nil {
+		return nil, fmt.Errorf("function registry is required")
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("function name is required")
+	}
+
+	signature := FunctionSignature{
+		Name:       name,
+		Input:      reflect.TypeOf((*In)(nil)).Elem(),
+		Output:     reflect.TypeOf((*Out)(nil)).Elem(),
+		Timeout:    opts.Timeout,
+		ErrorCodes: opts.ErrorCodes,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.functions[name]; ok {
+		if !reflect.DeepEqual(existing.signature, signature) {
+			return nil, fmt.Errorf("cloud function %s is already registered as func(%s) %s",
+				name, existing.signature.Input, existing.signature.Output)
+		}
+		return existing.function.(*TypedFunction[In, Out]), nil
+	}
+
+	function := DefineFunction[In, Out](name, opts)
+	r.functions[name] = registeredFunction{signature: signature, function: function}
+	return function, nil
+}
+
+// MustRegisterFunction is RegisterFunction for package-level declarations; it panics
+// on conflicting registrations.
+func MustRegisterFunction[In, Out any](r *FunctionRegistry, name string, opts FunctionOptions) *TypedFunction[In, Out] {
+	function, err := RegisterFunction[In, Out](r, name, opts)
+	if err != nil {
+		panic(err)
+	}
+	return function
+}
+
+// LookupFunction returns the typed function registered under name. It fails when
+// the function is missing or was registered with other types.
+func LookupFunction[In, Out any](r *FunctionRegistry, name string) (*TypedFunction[In, Out], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	existing, ok := r.functions[name]
+	if !ok {
+		return nil, fmt.Errorf("cloud function %s is not registered", name)
+	}
+	function, ok := existing.function.(*TypedFunction[In, Out])
+	if !ok {
+		return nil, fmt.Errorf("cloud function %s is registered as func(%s) %s",
+			name, existing.signature.Input, existing.signature.Output)
+	}
+	return function, nil
+}
+
+// Signature returns the signature registered under name.
+func (r *FunctionRegistry) Signature(name string) (FunctionSignature, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	existing, ok := r.functions[name]
+	return existing.signature, ok
+}
+
+// Signatures returns every registered signature sorted by name.
+func (r *FunctionRegistry) Signatures() []FunctionSignature {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	signatures := make([]FunctionSignature, 0, len(r.functions))
+	for _, existing := range r.functions {
+		signatures = append(signatures, existing.signature)
+	}
+	sort.Slice(signatures, func(i, j int) bool { return signatures[i].Name < signatures[j].Name })
+	return signatures
+}
@@ -0,0 +1,134 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type memberUpdateInput struct {
+	StoreID string `json:"store_id"`
+	Members []int  `json:"members"`
+}
+
+type memberUpdateOutput struct {
+	Updated int `json:"updated"`
+}
+
+func TestTypedFunction_Invoke(t *testing.T) {
+	var body map[string]any
+	code := "0"
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/namespaces/app_test/invoke/StoreMemberUpdate") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body = decodeTestBody(t, r)
+		switch code {
+		case "0":
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"updated": 2}})
+		default:
+			writeTestJSON(w, map[string]any{"code": code, "msg": "store not found"})
+		}
+	})
+	update := DefineFunction[memberUpdateInput, memberUpdateOutput]("StoreMemberUpdate", FunctionOptions{ErrorCodes: []string{"k_cf_*"}})
+
+	out, err := update.Invoke(context.Background(), client, memberUpdateInput{StoreID: "s1", Members: []int{1, 2}})
+	if err != nil || out.Updated != 2 {
+		t.Fatalf("unexpected result: %+v, %v", out, err)
+	}
+	params, _ := body["params"].(map[string]any)
+	if params["store_id"] != "s1" || len(params["members"].([]any)) != 2 {
+		t.Errorf("unexpected payload: %v", body)
+	}
+
+	code = "k_cf_001"
+	_, err = update.Invoke(context.Background(), client, memberUpdateInput{StoreID: "s2"})
+	var fnErr *FunctionError
+	if !errors.As(err, &fnErr) || !errors.Is(err, ErrFunctionFailed) || fnErr.Name != "StoreMemberUpdate" || fnErr.Code != "k_cf_001" {
+		t.Fatalf("expected function error, got %v", err)
+	}
+
+	// 平台错误码（限流、鉴权、权限、函数不存在等）不属于函数自身的错误
+	for _, platformCode := range []string{"99991400", "k_ident_013000", "k_ec_000015"} {
+		code = platformCode
+		_, err = update.Invoke(context.Background(), client, memberUpdateInput{StoreID: "s3"})
+		var apiErr *APIError
+		if errors.Is(err, ErrFunctionFailed) || !errors.As(err, &apiErr) || apiErr.Code != platformCode {
+			t.Fatalf("expected platform error for code %s, got %v", platformCode, err)
+		}
+	}
+
+	code = "k_cf_001"
+	plain := DefineFunction[memberUpdateInput, memberUpdateOutput]("StoreMemberUpdate", FunctionOptions{})
+	var apiErr *APIError
+	if _, err := plain.Invoke(context.Background(), client, memberUpdateInput{}); errors.Is(err, ErrFunctionFailed) || !errors.As(err, &apiErr) {
+		t.Fatalf("expected undeclared codes to stay platform errors, got %v", err)
+	}
+}
+
+func TestFunctionOptionsErrorCodes(t *testing.T) {
+	opts := FunctionOptions{ErrorCodes: []string{"E_STORE_NOT_FOUND", "biz_*"}}
+	cases := map[string]bool{
+		"E_STORE_NOT_FOUND": true,
+		"E_STORE":           false,
+		"biz_001":           true,
+		"k_ident_013000":    false,
+	}
+	for code, want := range cases {
+		if got := opts.isErrorCode(code); got != want {
+			t.Errorf("isErrorCode(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestTypedFunction_Timeout(t *testing.T) {
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+	slow := DefineFunction[struct{}, any]("Slow", FunctionOptions{Timeout: 20 * time.Millisecond})
+
+	start := time.Now()
+	_, err := slow.Invoke(context.Background(), client, struct{}{})
+	if err == nil || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("expected the invocation to time out quickly, got %v after %s", err, time.Since(start))
+	}
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrTimeout) && !strings.Contains(err.Error(), "deadline") {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+}
+
+func TestFunctionRegistry(t *testing.T) {
+	registry := NewFunctionRegistry()
+	update := MustRegisterFunction[memberUpdateInput, memberUpdateOutput](registry, "StoreMemberUpdate", FunctionOptions{Timeout: time.Second})
+
+	again, err := RegisterFunction[memberUpdateInput, memberUpdateOutput](registry, "StoreMemberUpdate", FunctionOptions{Timeout: time.Second})
+	if err != nil || again != update {
+		t.Fatalf("expected the existing function, got %v, %v", again, err)
+	}
+	if _, err := RegisterFunction[map[string]any, memberUpdateOutput](registry, "StoreMemberUpdate", FunctionOptions{}); err == nil {
+		t.Error("expected conflicting registration to fail")
+	}
+
+	found, err := LookupFunction[memberUpdateInput, memberUpdateOutput](registry, "StoreMemberUpdate")
+	if err != nil || found != update {
+		t.Fatalf("unexpected lookup result: %v, %v", found, err)
+	}
+	if _, err := LookupFunction[memberUpdateInput, string](registry, "StoreMemberUpdate"); err == nil {
+		t.Error("expected lookup with other types to fail")
+	}
+	if _, err := LookupFunction[struct{}, struct{}](registry, "Missing"); err == nil {
+		t.Error("expected lookup of a missing function to fail")
+	}
+
+	MustRegisterFunction[struct{}, struct{}](registry, "Ping", FunctionOptions{})
+	signatures := registry.Signatures()
+	if len(signatures) != 2 || signatures[0].Name != "Ping" || signatures[1].Input.Name() != "memberUpdateInput" || signatures[1].Timeout != time.Second {
+		t.Errorf("unexpected signatures: %+v", signatures)
+	}
+}
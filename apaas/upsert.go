@@ -0,0 +1,314 @@
+package apaas
+
+import (
+	"context"
+	"fmt"
+)
+
+// upsertLookupSize is the number of keys resolved per records_query request.
+const upsertLookupSize = 50
+
+// ObjectUpsertParams creates or updates records matched by key fields.
+type ObjectUpsertParams struct {
+	ObjectName string
+	Records    []map[string]any
+	KeyFields  []string // 业务主键字段，支持多个字段组合
+	Limit      int      // 每批次数量，默认 100
+}
+
+// UpsertAction is the write chosen for an input record.
+type UpsertAction string
+
+// Upsert actions.
+const (
+	UpsertActionCreate UpsertAction = "create"
+	UpsertActionUpdate UpsertAction = "update"
+	UpsertActionNone   UpsertAction = "none"
+)
+
+// UpsertItem is the outcome of one input record, in input order.
+type UpsertItem struct {
+	Index   int          `json:"index"`
+	ID      string       `json:"_id,omitempty"`
+	Action  UpsertAction `json:"action"`
+	Success bool         `json:"success"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// UpsertResult reports per-input outcomes of an upsert.
+type UpsertResult struct {
+	Total        int          `json:"total"`
+	Items        []UpsertItem `json:"items"`
+	CreatedCount int          `json:"createdCount"`
+	UpdatedCount int          `json:"updatedCount"`
+	FailedCount  int          `json:"failedCount"`
+}
+
+// Upsert creates records whose key is absent and updates records whose key already exists.
+// Existing records are looked up by KeyFields via records_query; writes go through the
+// batch create and update endpoints.
+func (s *ObjectService) Upsert(ctx context.Context, params ObjectUpsertParams) (*UpsertResult, error) {
+	ctx = withBulkPriority(ctx)
+
+	if params.Records == nil {
+		s.client.log(LoggerLevelError, "[object.upsert] Invalid records parameter: must be a non-empty array")
+		return nil, fmt.Errorf("参数 records 必须是一个数组")
+	}
+	if len(params.KeyFields) == 0 {
+		return nil, fmt.Errorf("at least one key field is required")
+	}
+
+	total := len(params.Records)
+	result := &UpsertResult{Total: total, Items: make([]UpsertItem, total)}
+	if total == 0 {
+		return result, nil
+	}
+
+	chunkSize := params.Limit
+	if chunkSize <= 0 {
+		chunkSize = 100
+	}
+
+	// 计算每条记录的业务主键，重复或缺失主键的记录直接标记失败
+	keys := make([]string, total)
+	firstByKey := make(map[string]int, total)
+	pending := make([]int, 0, total)
+	for index, record := range params.Records {
+		result.Items[index] = UpsertItem{Index: index, Action: UpsertActionNone}
+
+		key, err := recordKey(record, params.KeyFields)
+		if err != nil {
+			result.Items[index].Error = err.Error()
+			continue
+		}
+		if first, ok := firstByKey[key]; ok {
+			result.Items[index].Error = fmt.Sprintf("duplicate key %s (same as record %d)", key, first)
+			continue
+		}
+		firstByKey[key] = index
+		keys[index] = key
+		pending = append(pending, index)
+	}
+
+	existing, ambiguous, err := s.lookupKeys(ctx, params.ObjectName, params.KeyFields, params.Records, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	creates := make([]int, 0)
+	updates := make([]int, 0)
+	for _, index := range pending {
+		key := keys[index]
+		switch {
+		case ambiguous[key]:
+			result.Items[index].Error = fmt.Sprintf("key %s matches more than one existing record", key)
+		case existing[key] != "":
+			result.Items[index].Action = UpsertActionUpdate
+			result.Items[index].ID = existing[key]
+			updates = append(updates, index)
+		default:
+			result.Items[index].Action = UpsertActionCreate
+			creates = append(creates, index)
+		}
+	}
+
+	createRecords := make([]map[string]any, len(creates))
+	for i, index := range creates {
+		record := cloneMap(params.Records[index])
+		delete(record, "_id")
+		createRecords[i] = record
+	}
+	updateRecords := make([]map[string]any, len(updates))
+	for i, index := range updates {
+		record := cloneMap(params.Records[index])
+		record["_id"] = result.Items[index].ID
+		updateRecords[i] = record
+	}
+
+	// 预校验，避免部分批次已写入后才发现错误
+	if err := s.client.preflight(ctx, params.ObjectName, createRecords, false); err != nil {
+		return nil, err
+	}
+	if err := s.client.preflight(ctx, params.ObjectName, updateRecords, true); err != nil {
+		return nil, err
+	}
+	ctx = withPreflightDone(ctx)
+
+	s.client.log(LoggerLevelInfo, "[object.upsert] Upserting %s: create=%d, update=%d, skipped=%d", params.ObjectName, len(creates), len(updates), total-len(creates)-len(updates))
+
+	for start := 0; start < len(creates); start += chunkSize {
+		end := min(start+chunkSize, len(creates))
+		s.upsertCreateChunk(ctx, params.ObjectName, createRecords[start:end], creates[start:end], result)
+	}
+	for start := 0; start < len(updates); start += chunkSize {
+		end := min(start+chunkSize, len(updates))
+		s.upsertUpdateChunk(ctx, params.ObjectName, updateRecords[start:end], updates[start:end], result)
+	}
+
+	for _, item := range result.Items {
+		switch {
+		case !item.Success:
+			result.FailedCount++
+		case item.Action == UpsertActionCreate:
+			result.CreatedCount++
+		case item.Action == UpsertActionUpdate:
+			result.UpdatedCount++
+		}
+	}
+
+	s.client.log(LoggerLevelInfo, "[object.upsert] Upsert completed: total=%d, created=%d, updated=%d, failed=%d", result.Total, result.CreatedCount, result.UpdatedCount, result.FailedCount)
+	return result, nil
+}
+
+// lookupKeys resolves the existing record ID of each pending input's key.
+func (s *ObjectService) lookupKeys(ctx context.Context, objectName string, keyFields []string, records []map[string]any, pending []int) (map[string]string, map[string]bool, error) {
+	existing := make(map[string]string)
+	ambiguous := make(map[string]bool)
+
+	selectFields := append([]string{"_id"}, keyFields...)
+
+	for start := 0; start < len(pending); start += upsertLookupSize {
+		end := min(start+upsertLookupSize, len(pending))
+
+		filter := &RecordFilter{}
+		for _, index := range pending[start:end] {
+			group := &RecordFilter{}
+			for _, field := range keyFields {
+				group.Conditions = append(group.Conditions, Where(field, FilterEquals, records[index][field]))
+			}
+			if len(filter.Conditions) == 0 {
+				filter = group
+			} else {
+				filter = filter.Or(group)
+			}
+		}
+
+		found, err := s.Search.RecordsWithIterator(withoutProgress(ctx), ObjectRecordsIteratorParams{
+			ObjectName: objectName,
+			Data: map[string]any{
+				"select":    selectFields,
+				"page_size": 100,
+			},
+			Filter: filter,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to look up existing records: %w", err)
+		}
+
+		for _, record := range found.Items {
+			key, err := recordKey(record, keyFields)
+			if err != nil {
+				continue
+			}
+			if id, ok := existing[key]; ok && id != recordID(record) {
+				ambiguous[key] = true
+				continue
+			}
+			existing[key] = recordID(record)
+		}
+	}
+
+	s.client.log(LoggerLevelDebug, "[object.upsert] Existing records resolved: %s, matched=%d, ambiguous=%d", objectName, len(existing), len(ambiguous))
+	return existing, ambiguous, nil
+}
+
+// upsertCreateChunk creates one chunk and maps response items back to inputs by position.
+func (s *ObjectService) upsertCreateChunk(ctx context.Context, objectName string, chunk []map[string]any, indexes []int, result *UpsertResult) {
+	resp, err := s.Create.Records(ctx, ObjectCreateRecordsParams{ObjectName: objectName, Records: chunk})
+	items, failure := batchResponseItems(resp, err, "Creation")
+	if failure != "" {
+		s.client.log(LoggerLevelError, "[object.upsert] Create chunk failed: %s", failure)
+		markUpsertFailed(result, indexes, failure)
+		return
+	}
+	if len(items) != len(chunk) {
+		markUpsertFailed(result, indexes, fmt.Sprintf("unexpected create response: %d items for %d records", len(items), len(chunk)))
+		return
+	}
+
+	for i, index := range indexes {
+		id, success, errMsg := batchItemOutcome(items[i], true)
+		result.Items[index].ID = id
+		result.Items[index].Success = success
+		result.Items[index].Error = errMsg
+	}
+}
+
+// upsertUpdateChunk updates one chunk and maps response items back to inputs by ID.
+func (s *ObjectService) upsertUpdateChunk(ctx context.Context, objectName string, chunk []map[string]any, indexes []int, result *UpsertResult) {
+	resp, err := s.Update.Records(ctx, ObjectUpdateRecordsParams{ObjectName: objectName, Records: chunk})
+	items, failure := batchResponseItems(resp, err, "Update")
+	if failure != "" {
+		s.client.log(LoggerLevelError, "[object.upsert] Update chunk failed: %s", failure)
+		markUpsertFailed(result, indexes, failure)
+		return
+	}
+
+	outcomes := make(map[string]map[string]any, len(items))
+	for _, item := range items {
+		id, _, _ := batchItemOutcome(item, false)
+		outcomes[id] = item
+	}
+
+	for _, index := range indexes {
+		item, ok := outcomes[result.Items[index].ID]
+		if !ok {
+			result.Items[index].Error = "record missing from update response"
+			continue
+		}
+		_, success, errMsg := batchItemOutcome(item, false)
+		result.Items[index].Success = success
+		result.Items[index].Error = errMsg
+	}
+}
+
+func markUpsertFailed(result *UpsertResult, indexes []int, errMsg string) {
+	for _, index := range indexes {
+		result.Items[index].Success = false
+		result.Items[index].Error = errMsg
+	}
+}
+
+// batchResponseItems extracts the items of a records_batch response, or a failure
+// message that applies to the whole chunk.
+func batchResponseItems(resp *APIResponse, err error, action string) ([]map[string]any, string) {
+	if err != nil {
+		return nil, err.Error()
+	}
+	if resp.Code != "0" {
+		if resp.Msg != "" {
+			return nil, resp.Msg
+		}
+		return nil, fmt.Sprintf("%s failed with code %s", action, resp.Code)
+	}
+
+	var page struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := resp.DecodeData(&page); err != nil {
+		return nil, err.Error()
+	}
+	return page.Items, ""
+}
+
+// batchItemOutcome reads the ID, success flag and error of a records_batch response item.
+// defaultSuccess applies when the item has no success field.
+func batchItemOutcome(item map[string]any, defaultSuccess bool) (string, bool, string) {
+	id := "unknown"
+	if idVal, ok := item["_id"]; ok && idVal != nil {
+		id = recordID(item)
+	}
+
+	success := defaultSuccess
+	if successBool, ok := item["success"].(bool); ok {
+		success = successBool
+	}
+
+	errMsg := ""
+	if !success {
+		if errorStr, ok := item["error"].(string); ok {
+			errMsg = errorStr
+		}
+	}
+	return id, success, errMsg
+}
@@ -0,0 +1,80 @@
+package apaas
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestObjectUpsert(t *testing.T) {
+	var created, updated []any
+	client := newTestClient(t, ClientOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		body := decodeTestBody(t, r)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/records_query"):
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{
+				"items": []any{
+					map[string]any{"_id": "100", "sku": "A"},
+					map[string]any{"_id": "200", "sku": "C"},
+					map[string]any{"_id": "201", "sku": "C"},
+				},
+			}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/records_batch"):
+			created = body["records"].([]any)
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{
+				"items": []any{map[string]any{"_id": "300", "success": true}},
+			}})
+		case r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/records_batch"):
+			updated = body["records"].([]any)
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{
+				"items": []any{map[string]any{"_id": "100", "success": true}},
+			}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	result, err := client.Object.Upsert(context.Background(), ObjectUpsertParams{
+		ObjectName: "object_product",
+		KeyFields:  []string{"sku"},
+		Records: []map[string]any{
+			{"sku": "A", "price": 1},
+			{"sku": "B", "price": 2},
+			{"sku": "C", "price": 3},
+			{"sku": "A", "price": 4},
+			{"price": 5},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		action  UpsertAction
+		id      string
+		success bool
+	}{
+		{UpsertActionUpdate, "100", true},
+		{UpsertActionCreate, "300", true},
+		{UpsertActionNone, "", false}, // ambiguous key
+		{UpsertActionNone, "", false}, // duplicate input key
+		{UpsertActionNone, "", false}, // missing key
+	}
+	for i, w := range want {
+		item := result.Items[i]
+		if item.Action != w.action || item.ID != w.id || item.Success != w.success {
+			t.Errorf("item %d = %+v, want action=%s id=%s success=%v", i, item, w.action, w.id, w.success)
+		}
+	}
+	if result.CreatedCount != 1 || result.UpdatedCount != 1 || result.FailedCount != 3 {
+		t.Errorf("unexpected counts: %+v", result)
+	}
+
+	if len(created) != 1 || created[0].(map[string]any)["sku"] != "B" {
+		t.Errorf("unexpected create payload: %v", created)
+	}
+	if len(updated) != 1 || updated[0].(map[string]any)["_id"] != "100" {
+		t.Errorf("unexpected update payload: %v", updated)
+	}
+}
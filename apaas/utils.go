@@ -1,5 +1,10 @@
 package apaas
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 // cloneMap creates a shallow copy of the provided map to avoid unexpected mutations.
 func cloneMap(m map[string]any) map[string]any {
 	if m == nil {
@@ -12,3 +17,25 @@ func cloneMap(m map[string]any) map[string]any {
 	}
 	return c
 }
+
+// mustJSON encodes v as compact JSON, falling back to an empty string on failure.
+func mustJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// jsonEqual compares two values by their JSON encoding, so that e.g. int(1) and float64(1) match.
+func jsonEqual(a, b any) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(left, right)
+}
@@ -0,0 +1,302 @@
+package apaas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// ObjectValidateParams validates records against an object's field metadata.
+type ObjectValidateParams struct {
+	ObjectName string
+	Records    []map[string]any
+	// Partial skips required-field checks, e.g. for updates that patch a subset of fields.
+	Partial bool
+}
+
+// RecordValidationResult holds the validation errors of one input record.
+type RecordValidationResult struct {
+	Index  int                `json:"index"`
+	Errors []*ValidationError `json:"errors"`
+}
+
+// Validate checks records against the object's field metadata without sending them.
+// Only records with at least one error are returned. Field metadata is read through
+// the metadata cache when it is enabled.
+func (s *ObjectService) Validate(ctx context.Context, params ObjectValidateParams) ([]RecordValidationResult, error) {
+	object, err := s.Metadata.FieldsTyped(ctx, ObjectMetadataFieldsParams{ObjectName: params.ObjectName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load field metadata of %s: %w", params.ObjectName, err)
+	}
+
+	results := make([]RecordValidationResult, 0)
+	for index, record := range params.Records {
+		if errs := validateRecord(object, record, params.Partial); len(errs) > 0 {
+			results = append(results, RecordValidationResult{Index: index, Errors: errs})
+		}
+	}
+
+	s.client.log(LoggerLevelDebug, "[object.validate] Records validated: %s, total=%d, invalid=%d", params.ObjectName, len(params.Records), len(results))
+	return results, nil
+}
+
+type preflightDoneKey struct{}
+
+// withPreflightDone marks the records of nested writes as already validated, so that
+// batch helpers validate all records once up front instead of once more per chunk.
+func withPreflightDone(ctx context.Context) context.Context {
+	return context.WithValue(ctx, preflightDoneKey{}, true)
+}
+
+// preflight validates records before a write when ClientOptions.ValidateRecords is set.
+func (c *Client) preflight(ctx context.Context, objectName string, records []map[string]any, partial bool) error {
+	if !c.validateRecords || len(records) == 0 || ctx.Value(preflightDoneKey{}) != nil {
+		return nil
+	}
+
+	results, err := c.Object.Validate(ctx, ObjectValidateParams{
+		ObjectName: objectName,
+		Records:    records,
+		Partial:    partial,
+	})
+	if err != nil {
+		return err
+	}
+	if len(results) > 0 {
+		c.log(LoggerLevelWarn, "[object.validate] %d of %d records failed validation: %s", len(results), len(records), objectName)
+		return &RecordsValidationError{ObjectName: objectName, Results: results}
+	}
+	return nil
+}
+
+// validateRecord checks a single record and returns all violations.
+func validateRecord(object *Object, record map[string]any, partial bool) []*ValidationError {
+	errs := make([]*ValidationError, 0)
+
+	for _, name := range sortedKeys(record) {
+		value := record[name]
+		if name == "_id" {
+			continue
+		}
+
+		field, ok := object.Field(name)
+		if !ok {
+			errs = append(errs, &ValidationError{Field: name, Message: "unknown field"})
+			continue
+		}
+		if isReadOnlyField(field) {
+			errs = append(errs, &ValidationError{Field: name, Message: fmt.Sprintf("field of type %s is read-only", field.Type)})
+			continue
+		}
+		if value == nil {
+			if field.Required && !partial {
+				errs = append(errs, &ValidationError{Field: name, Message: "required field is null"})
+			}
+			continue
+		}
+		if msg := validateValue(field, value); msg != "" {
+			errs = append(errs, &ValidationError{Field: name, Message: msg})
+		}
+	}
+
+	if !partial {
+		for i := range object.Fields {
+			field := &object.Fields[i]
+			if !field.Required || isReadOnlyField(field) || strings.HasPrefix(field.APIName, "_") {
+				continue
+			}
+			value, ok := record[field.APIName]
+			if ok && value == nil {
+				continue // already reported as null above
+			}
+			if !ok || isEmptyValue(value) {
+				errs = append(errs, &ValidationError{Field: field.APIName, Message: "required field is missing"})
+			}
+		}
+	}
+
+	return errs
+}
+
+func validateValue(field *Field, value any) string {
+	switch {
+	case field.Type.IsText():
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("expected string for %s field, got %T", field.Type, value)
+		}
+		if field.MaxLength > 0 && utf8.RuneCountInString(text) > field.MaxLength {
+			return fmt.Sprintf("text length %d exceeds max length %d", utf8.RuneCountInString(text), field.MaxLength)
+		}
+
+	case field.Type.IsNumeric():
+		number, ok := toFloat(value)
+		if !ok {
+			return fmt.Sprintf("expected number for %s field, got %T", field.Type, value)
+		}
+		if field.Type == FieldTypeBigint && number != math.Trunc(number) {
+			return fmt.Sprintf("expected integer, got %v", number)
+		}
+		if field.MinValue != nil && number < *field.MinValue {
+			return fmt.Sprintf("value %v is less than minimum %v", number, *field.MinValue)
+		}
+		if field.MaxValue != nil && number > *field.MaxValue {
+			return fmt.Sprintf("value %v is greater than maximum %v", number, *field.MaxValue)
+		}
+
+	case field.Type == FieldTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("expected boolean, got %T", value)
+		}
+
+	case field.Type == FieldTypeDate || field.Type == FieldTypeDateTime:
+		if _, ok := value.(string); ok {
+			return ""
+		}
+		if _, ok := toFloat(value); !ok {
+			return fmt.Sprintf("expected timestamp or date string, got %T", value)
+		}
+
+	case field.Type == FieldTypeMultilingual:
+		if _, ok := value.(map[string]any); !ok {
+			if _, ok := value.(map[string]string); !ok {
+				return fmt.Sprintf("expected language map, got %T", value)
+			}
+		}
+
+	case field.Type == FieldTypeOption:
+		values, msg := optionValues(value, field.Multiple)
+		if msg != "" {
+			return msg
+		}
+		if field.Options == nil || len(field.Options.Options) == 0 {
+			return ""
+		}
+		for _, v := range values {
+			if !field.Options.Has(v) {
+				return fmt.Sprintf("invalid option value %q", v)
+			}
+		}
+
+	case field.Type == FieldTypeLookup || field.Type == FieldTypeReference:
+		if field.Multiple {
+			var items []any
+			switch v := value.(type) {
+			case []any:
+				items = v
+			case []map[string]any:
+				for _, item := range v {
+					items = append(items, item)
+				}
+			default:
+				return fmt.Sprintf("expected list of references, got %T", value)
+			}
+			for _, item := range items {
+				if !isReferenceValue(item) {
+					return fmt.Sprintf("invalid reference %v", item)
+				}
+			}
+		} else if !isReferenceValue(value) {
+			return fmt.Sprintf("invalid reference %v", value)
+		}
+	}
+
+	return ""
+}
+
+func isReadOnlyField(field *Field) bool {
+	return field.Type == FieldTypeAutoNumber || field.Type == FieldTypeFormula || field.Type == FieldTypeRollup
+}
+
+func isEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	case []map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// optionValues extracts option API names from a single value or a list.
+func optionValues(value any, multiple bool) ([]string, string) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, ""
+	case []string:
+		if !multiple {
+			return nil, "expected a single option, got a list"
+		}
+		return v, ""
+	case []any:
+		if !multiple {
+			return nil, "expected a single option, got a list"
+		}
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Sprintf("expected option API name, got %T", item)
+			}
+			values = append(values, s)
+		}
+		return values, ""
+	}
+	return nil, fmt.Sprintf("expected option API name, got %T", value)
+}
+
+// isReferenceValue accepts a record ID or a {"_id": ...} object.
+func isReferenceValue(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return v != ""
+	case map[string]any:
+		id, ok := v["_id"]
+		return ok && id != nil
+	}
+	_, ok := toFloat(value)
+	return ok
+}
+
+// toFloat converts JSON and Go numeric values to float64.
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,146 @@
+package apaas
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func testValidationObject() *Object {
+	maxScore := 100.0
+	minScore := 0.0
+	return &Object{
+		APIName: "object_store",
+		Fields: []Field{
+			{APIName: "_id", Type: FieldTypeBigint},
+			{APIName: "name", Type: FieldTypeText, Required: true, MaxLength: 5},
+			{APIName: "score", Type: FieldTypeNumber, MinValue: &minScore, MaxValue: &maxScore},
+			{APIName: "count", Type: FieldTypeBigint},
+			{APIName: "active", Type: FieldTypeBoolean},
+			{APIName: "status", Type: FieldTypeOption, Options: &OptionSet{Options: []Option{
+				{APIName: "open", Active: true},
+				{APIName: "closed", Active: true},
+			}}},
+			{APIName: "tags", Type: FieldTypeOption, Multiple: true, Options: &OptionSet{Options: []Option{
+				{APIName: "a", Active: true},
+			}}},
+			{APIName: "manager", Type: FieldTypeLookup},
+			{APIName: "members", Type: FieldTypeLookup, Multiple: true},
+			{APIName: "code", Type: FieldTypeAutoNumber},
+		},
+	}
+}
+
+func TestValidateRecord(t *testing.T) {
+	object := testValidationObject()
+
+	tests := []struct {
+		name    string
+		record  map[string]any
+		partial bool
+		fields  []string
+	}{
+		{"valid", map[string]any{"name": "abc", "score": 10, "status": "open", "tags": []any{"a"}, "manager": map[string]any{"_id": 1}}, false, nil},
+		{"missing required", map[string]any{"score": 1}, false, []string{"name"}},
+		{"partial skips required", map[string]any{"_id": "1", "score": 1}, true, nil},
+		{"unknown field", map[string]any{"name": "a", "nope": 1}, false, []string{"nope"}},
+		{"text too long", map[string]any{"name": "abcdef"}, false, []string{"name"}},
+		{"type mismatch", map[string]any{"name": "a", "score": "10", "active": "yes"}, false, []string{"active", "score"}},
+		{"out of range", map[string]any{"name": "a", "score": 101}, false, []string{"score"}},
+		{"non integer bigint", map[string]any{"name": "a", "count": 1.5}, false, []string{"count"}},
+		{"invalid option", map[string]any{"name": "a", "status": "paused"}, false, []string{"status"}},
+		{"list for single option", map[string]any{"name": "a", "status": []any{"open"}}, false, []string{"status"}},
+		{"read only", map[string]any{"name": "a", "code": "X-1"}, false, []string{"code"}},
+		{"required null", map[string]any{"name": nil}, false, []string{"name"}},
+		{"multi lookup list", map[string]any{"name": "a", "members": []any{map[string]any{"_id": 1}}}, false, nil},
+		{"multi lookup maps", map[string]any{"name": "a", "members": []map[string]any{{"_id": 1}}}, false, nil},
+		{"invalid multi lookup list item", map[string]any{"name": "a", "members": []any{map[string]any{"name": "x"}}}, false, []string{"members"}},
+		{"invalid multi lookup map item", map[string]any{"name": "a", "members": []map[string]any{{"name": "x"}}}, false, []string{"members"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateRecord(object, tt.record, tt.partial)
+			got := make([]string, 0, len(errs))
+			for _, err := range errs {
+				got = append(got, err.Field)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("expected errors on %v, got %v", tt.fields, errs)
+			}
+		})
+	}
+}
+
+func TestCreateRecords_PreflightValidation(t *testing.T) {
+	writes := 0
+	client := newTestClient(t, ClientOptions{ValidateRecords: true}, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/meta/objects/object_store"):
+			writeTestJSON(w, map[string]any{
+				"code": "0",
+				"data": map[string]any{
+					"apiName": "object_store",
+					"fields": []any{
+						map[string]any{"apiName": "name", "type": map[string]any{"name": "text", "settings": map[string]any{"required": true}}},
+					},
+				},
+			})
+		default:
+			writes++
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"items": []any{}}})
+		}
+	})
+
+	_, err := client.Object.Create.RecordsWithIterator(context.Background(), ObjectCreateRecordsIteratorParams{
+		ObjectName: "object_store",
+		Records:    []map[string]any{{"name": "ok"}, {"title": "missing name"}},
+	})
+
+	var validationErr *RecordsValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected RecordsValidationError, got %v", err)
+	}
+	if len(validationErr.Results) != 1 || validationErr.Results[0].Index != 1 {
+		t.Errorf("expected record 1 to fail, got %+v", validationErr.Results)
+	}
+
+	var fieldErr *ValidationError
+	if !errors.As(err, &fieldErr) {
+		t.Error("expected individual ValidationError to be reachable via errors.As")
+	}
+	if writes != 0 {
+		t.Errorf("expected no write requests, got %d", writes)
+	}
+}
+
+func TestRecordsWithIterator_PreflightOnce(t *testing.T) {
+	client := newTestClient(t, ClientOptions{ValidateRecords: true}, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/meta/objects/object_store"):
+			writeTestJSON(w, map[string]any{
+				"code": "0",
+				"data": map[string]any{
+					"apiName": "object_store",
+					"fields":  []any{map[string]any{"apiName": "name", "type": map[string]any{"name": "text"}}},
+				},
+			})
+		default:
+			writeTestJSON(w, map[string]any{"code": "0", "data": map[string]any{"items": []any{}}})
+		}
+	})
+
+	_, err := client.Object.Create.RecordsWithIterator(context.Background(), ObjectCreateRecordsIteratorParams{
+		ObjectName: "object_store",
+		Records:    []map[string]any{{"name": "a"}, {"name": "b"}, {"name": "c"}},
+		Limit:      1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats := client.MetadataCache().Stats(); stats.Hits+stats.Misses != 1 {
+		t.Errorf("expected the records to be validated once, got %d metadata lookups", stats.Hits+stats.Misses)
+	}
+}
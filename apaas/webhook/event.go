@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event types dispatched to the typed handlers.
+const (
+	EventRecordCreated = "record.created"
+	EventRecordUpdated = "record.updated"
+	EventRecordDeleted = "record.deleted"
+	EventFlowCompleted = "flow.completed"
+)
+
+// Event is a decoded callback envelope.
+type Event struct {
+	ID         string          `json:"event_id"`
+	Type       string          `json:"event_type"`
+	CreateTime time.Time       `json:"-"`
+	AppID      string          `json:"app_id"`
+	TenantKey  string          `json:"tenant_key"`
+	Namespace  string          `json:"namespace"`
+	Payload    json.RawMessage `json:"-"` // event 字段的原始内容
+}
+
+// Decode unmarshals the event payload into v.
+func (e *Event) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// RecordEvent is the payload of record.created, record.updated and record.deleted.
+type RecordEvent struct {
+	ObjectAPIName string         `json:"object_api_name"`
+	RecordID      string         `json:"record_id"`
+	Record        map[string]any `json:"record,omitempty"`
+	// OldRecord holds the previous values of record.updated and record.deleted events.
+	OldRecord map[string]any `json:"old_record,omitempty"`
+	Operator  *Operator      `json:"operator,omitempty"`
+}
+
+// FlowCompletedEvent is the payload of flow.completed.
+type FlowCompletedEvent struct {
+	FlowAPIName string         `json:"flow_api_name"`
+	InstanceID  string         `json:"instance_id"`
+	Status      string         `json:"status"`
+	Output      map[string]any `json:"output,omitempty"`
+	ErrorMsg    string         `json:"error_msg,omitempty"`
+}
+
+// Operator identifies the user who triggered an event.
+type Operator struct {
+	ID    json.Number `json:"_id"`
+	Email string      `json:"email,omitempty"`
+}
+
+// envelope covers both the 2.0 schema (header + event) and flat 1.0 callbacks.
+type envelope struct {
+	Schema    string          `json:"schema"`
+	Type      string          `json:"type"`
+	Token     string          `json:"token"`
+	Challenge string          `json:"challenge"`
+	Encrypt   string          `json:"encrypt"`
+	UUID      string          `json:"uuid"`
+	Header    *envelopeHeader `json:"header"`
+	Event     json.RawMessage `json:"event"`
+}
+
+type envelopeHeader struct {
+	EventID    string `json:"event_id"`
+	EventType  string `json:"event_type"`
+	CreateTime string `json:"create_time"`
+	Token      string `json:"token"`
+	AppID      string `json:"app_id"`
+	TenantKey  string `json:"tenant_key"`
+	Namespace  string `json:"namespace"`
+}
+
+func (e *envelope) token() string {
+	if e.Header != nil && e.Header.Token != "" {
+		return e.Header.Token
+	}
+	return e.Token
+}
@@ -0,0 +1,347 @@
+// Package webhook receives aPaaS event callbacks.
+//
+// Handler verifies the signature and verification token of each delivery, decrypts
+// encrypted payloads, answers URL verification challenges, drops retried deliveries
+// of an already handled event and dispatches the decoded event to registered handlers.
+//
+//	h := webhook.NewHandler(webhook.Options{
+//		VerificationToken: "token",
+//		EncryptKey:        "key",
+//	})
+//	h.OnRecordCreated(func(ctx context.Context, event *webhook.Event, record webhook.RecordEvent) error {
+//		return nil
+//	})
+//	http.Handle("/apaas/events", h)
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ennann/apaas-oapi-go-client/apaas"
+)
+
+// Callback request headers used for signature verification.
+const (
+	HeaderTimestamp = "X-Lark-Request-Timestamp"
+	HeaderNonce     = "X-Lark-Request-Nonce"
+	HeaderSignature = "X-Lark-Signature"
+)
+
+// maxBodySize limits the size of a callback body.
+const maxBodySize = 1 << 20
+
+// Verification errors.
+var (
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	ErrInvalidToken     = errors.New("webhook: invalid verification token")
+	ErrExpired          = errors.New("webhook: request timestamp outside tolerance")
+)
+
+// Options configures a Handler.
+type Options struct {
+	// VerificationToken is compared with the token carried by every callback. Empty skips the check.
+	VerificationToken string
+	// EncryptKey enables signature verification and decryption of encrypted payloads.
+	EncryptKey string
+	// Tolerance bounds the age of the signed timestamp, 5 minutes by default.
+	Tolerance time.Duration
+	// Deduper drops repeated deliveries; an in-memory deduper keeping IDs for 24 hours is used when nil.
+	Deduper Deduper
+	Logger  apaas.Logger
+}
+
+// HandlerFunc handles an event of any type.
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// Handler is an http.Handler receiving aPaaS event callbacks.
+type Handler struct {
+	opts     Options
+	now      func() time.Time
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+	fallback HandlerFunc
+}
+
+// NewHandler returns a Handler without registered event handlers.
+func NewHandler(opts Options) *Handler {
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = 5 * time.Minute
+	}
+	if opts.Deduper == nil {
+		opts.Deduper = NewMemoryDeduper(24 * time.Hour)
+	}
+	return &Handler{opts: opts, now: time.Now, handlers: make(map[string]HandlerFunc)}
+}
+
+// On registers fn for an event type, replacing any previous handler.
+func (h *Handler) On(eventType string, fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[eventType] = fn
+}
+
+// OnUnhandled registers fn for event types without a handler. Such events are
+// acknowledged and ignored by default.
+func (h *Handler) OnUnhandled(fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fallback = fn
+}
+
+// OnRecordCreated registers a handler for record.created events.
+func (h *Handler) OnRecordCreated(fn func(ctx context.Context, event *Event, record RecordEvent) error) {
+	h.On(EventRecordCreated, typed(fn))
+}
+
+// OnRecordUpdated registers a handler for record.updated events.
+func (h *Handler) OnRecordUpdated(fn func(ctx context.Context, event *Event, record RecordEvent) error) {
+	h.On(EventRecordUpdated, typed(fn))
+}
+
+// OnRecordDeleted registers a handler for record.deleted events.
+func (h *Handler) OnRecordDeleted(fn func(ctx context.Context, event *Event, record RecordEvent) error) {
+	h.On(EventRecordDeleted, typed(fn))
+}
+
+// OnFlowCompleted registers a handler for flow.completed events.
+func (h *Handler) OnFlowCompleted(fn func(ctx context.Context, event *Event, flow FlowCompletedEvent) error) {
+	h.On(EventFlowCompleted, typed(fn))
+}
+
+func typed[T any](fn func(ctx context.Context, event *Event, payload T) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var payload T
+		if err := event.Decode(&payload); err != nil {
+			return fmt.Errorf("failed to decode %s event: %w", event.Type, err)
+		}
+		return fn(ctx, event, payload)
+	}
+}
+
+// ServeHTTP implements http.Handler. Verification failures answer 401, malformed
+// bodies 400 and handler errors 500 so that the platform retries the delivery.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil || len(body) > maxBodySize {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+
+	env, err := h.decode(r.Header, body)
+	if err != nil {
+		h.log(apaas.LoggerLevelWarn, "[webhook] Rejected callback: %v", err)
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrInvalidSignature) || errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrExpired) {
+			status = http.StatusUnauthorized
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	if env.Type == "url_verification" {
+		writeJSON(w, map[string]string{"challenge": env.Challenge})
+		return
+	}
+
+	event, err := newEvent(env)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if event.ID != "" && h.opts.Deduper.Seen(event.ID) {
+		h.log(apaas.LoggerLevelDebug, "[webhook] Duplicate delivery ignored: %s (%s)", event.ID, event.Type)
+		writeJSON(w, map[string]string{"msg": "duplicate"})
+		return
+	}
+
+	// 只有处理成功才记录事件 ID，处理失败（包括 panic）时平台重试会再次分发
+	if err := h.dispatch(r.Context(), event); err != nil {
+		h.log(apaas.LoggerLevelError, "[webhook] Handler failed: %s (%s): %v", event.ID, event.Type, err)
+		http.Error(w, "handler failed", http.StatusInternalServerError)
+		return
+	}
+	if event.ID != "" {
+		h.opts.Deduper.MarkSeen(event.ID)
+	}
+
+	h.log(apaas.LoggerLevelDebug, "[webhook] Event handled: %s (%s)", event.ID, event.Type)
+	writeJSON(w, map[string]string{"msg": "success"})
+}
+
+// decode verifies the request and returns the (decrypted) envelope.
+func (h *Handler) decode(header http.Header, body []byte) (*envelope, error) {
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, fmt.Errorf("invalid json body: %w", err)
+	}
+
+	signed := header.Get(HeaderSignature) != ""
+	if h.opts.EncryptKey != "" && signed {
+		if err := h.verifySignature(header, body); err != nil {
+			return nil, err
+		}
+	}
+
+	if env.Encrypt != "" {
+		if h.opts.EncryptKey == "" {
+			return nil, errors.New("encrypted payload received but no encrypt key configured")
+		}
+		plaintext, err := Decrypt(env.Encrypt, h.opts.EncryptKey)
+		if err != nil {
+			return nil, err
+		}
+		env = envelope{}
+		if err := json.Unmarshal(plaintext, &env); err != nil {
+			return nil, fmt.Errorf("invalid decrypted payload: %w", err)
+		}
+	}
+
+	// URL 校验请求不带签名头，其余请求在配置了 EncryptKey 时必须带签名
+	if h.opts.EncryptKey != "" && !signed && env.Type != "url_verification" {
+		return nil, ErrInvalidSignature
+	}
+
+	if h.opts.VerificationToken != "" &&
+		subtle.ConstantTimeCompare([]byte(env.token()), []byte(h.opts.VerificationToken)) != 1 {
+		return nil, ErrInvalidToken
+	}
+	return &env, nil
+}
+
+func (h *Handler) verifySignature(header http.Header, body []byte) error {
+	timestamp := header.Get(HeaderTimestamp)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := h.now().Sub(time.Unix(seconds, 0)); age > h.opts.Tolerance || age < -h.opts.Tolerance {
+		return ErrExpired
+	}
+
+	expected := Signature(timestamp, header.Get(HeaderNonce), h.opts.EncryptKey, body)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(header.Get(HeaderSignature))) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (h *Handler) dispatch(ctx context.Context, event *Event) error {
+	h.mu.RLock()
+	fn, ok := h.handlers[event.Type]
+	if !ok {
+		fn = h.fallback
+	}
+	h.mu.RUnlock()
+
+	if fn == nil {
+		h.log(apaas.LoggerLevelDebug, "[webhook] No handler for event type: %s", event.Type)
+		return nil
+	}
+	return fn(ctx, event)
+}
+
+func (h *Handler) log(level apaas.LoggerLevel, format string, args ...any) {
+	if h.opts.Logger != nil {
+		h.opts.Logger.Log(level, format, args...)
+	}
+}
+
+// newEvent builds an Event from a 2.0 envelope or a flat 1.0 callback.
+func newEvent(env *envelope) (*Event, error) {
+	event := &Event{Payload: env.Event}
+	if env.Header != nil {
+		event.ID = env.Header.EventID
+		event.Type = env.Header.EventType
+		event.AppID = env.Header.AppID
+		event.TenantKey = env.Header.TenantKey
+		event.Namespace = env.Header.Namespace
+		if ms, err := strconv.ParseInt(env.Header.CreateTime, 10, 64); err == nil {
+			event.CreateTime = time.UnixMilli(ms)
+		}
+	} else {
+		event.ID = env.UUID
+		var inner struct {
+			Type string `json:"type"`
+		}
+		if len(env.Event) > 0 {
+			if err := json.Unmarshal(env.Event, &inner); err != nil {
+				return nil, fmt.Errorf("invalid event: %w", err)
+			}
+		}
+		event.Type = inner.Type
+	}
+
+	if event.Type == "" {
+		return nil, errors.New("missing event type")
+	}
+	return event, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// Deduper remembers handled event IDs so that retried deliveries are dropped.
+// An event is marked only after its handlers succeed, so deliveries that arrive
+// while the first one is still being handled are dispatched again.
+type Deduper interface {
+	// Seen reports whether id was handled successfully before.
+	Seen(id string) bool
+	// MarkSeen records that id was handled successfully.
+	MarkSeen(id string)
+}
+
+// MemoryDeduper keeps event IDs in memory for a fixed time.
+type MemoryDeduper struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	seen      map[string]time.Time
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// NewMemoryDeduper returns a deduper remembering IDs for ttl.
+func NewMemoryDeduper(ttl time.Duration) *MemoryDeduper {
+	return &MemoryDeduper{ttl: ttl, seen: make(map[string]time.Time), now: time.Now}
+}
+
+// Seen implements Deduper.
+func (d *MemoryDeduper) Seen(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	expires, ok := d.seen[id]
+	return ok && !d.now().After(expires)
+}
+
+// MarkSeen implements Deduper.
+func (d *MemoryDeduper) MarkSeen(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	if now.Sub(d.lastPrune) > time.Minute {
+		for seenID, expires := range d.seen {
+			if now.After(expires) {
+				delete(d.seen, seenID)
+			}
+		}
+		d.lastPrune = now
+	}
+	d.seen[id] = now.Add(d.ttl)
+}
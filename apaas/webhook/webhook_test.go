@@ -0,0 +1,188 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testToken      = "verification-token"
+	testEncryptKey = "encrypt-key"
+)
+
+// encrypt mirrors the platform's AES-256-CBC encryption.
+func encrypt(t *testing.T, plaintext []byte, key string) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append(append([]byte(nil), plaintext...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	iv := []byte("0123456789abcdef")
+	out := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, padded)
+	return base64.StdEncoding.EncodeToString(append(iv, out...))
+}
+
+// signedRequest builds an encrypted, signed callback for payload.
+func signedRequest(t *testing.T, payload any, now time.Time) *http.Request {
+	t.Helper()
+	plaintext, _ := json.Marshal(payload)
+	body, _ := json.Marshal(map[string]string{"encrypt": encrypt(t, plaintext, testEncryptKey)})
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, "nonce")
+	req.Header.Set(HeaderSignature, Signature(timestamp, "nonce", testEncryptKey, body))
+	return req
+}
+
+func recordCreated(eventID string) map[string]any {
+	return map[string]any{
+		"schema": "2.0",
+		"header": map[string]any{"event_id": eventID, "event_type": EventRecordCreated, "token": testToken, "create_time": "1700000000000"},
+		"event":  map[string]any{"object_api_name": "object_store", "record_id": "r1", "record": map[string]any{"name": "A"}},
+	}
+}
+
+func newTestHandler() *Handler {
+	return NewHandler(Options{VerificationToken: testToken, EncryptKey: testEncryptKey})
+}
+
+func TestHandler_DispatchAndDedupe(t *testing.T) {
+	h := newTestHandler()
+	now := time.Now()
+
+	failures := 1
+	var got []RecordEvent
+	h.OnRecordCreated(func(ctx context.Context, event *Event, record RecordEvent) error {
+		if failures > 0 {
+			failures--
+			return errors.New("temporary failure")
+		}
+		if event.ID != "ev1" || event.CreateTime.UnixMilli() != 1700000000000 {
+			t.Errorf("unexpected event: %+v", event)
+		}
+		got = append(got, record)
+		return nil
+	})
+
+	statuses := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, signedRequest(t, recordCreated("ev1"), now))
+		statuses = append(statuses, rec.Code)
+	}
+
+	// failed delivery is retried, the redelivery after success is dropped
+	if statuses[0] != http.StatusInternalServerError || statuses[1] != http.StatusOK || statuses[2] != http.StatusOK {
+		t.Errorf("statuses = %v", statuses)
+	}
+	if len(got) != 1 || got[0].ObjectAPIName != "object_store" || got[0].Record["name"] != "A" {
+		t.Errorf("unexpected records: %+v", got)
+	}
+}
+
+func TestHandler_PanickingHandlerIsRedelivered(t *testing.T) {
+	h := newTestHandler()
+	now := time.Now()
+
+	calls := 0
+	h.OnRecordCreated(func(ctx context.Context, event *Event, record RecordEvent) error {
+		if calls++; calls == 1 {
+			panic("handler crashed")
+		}
+		return nil
+	})
+
+	func() {
+		defer func() { _ = recover() }()
+		h.ServeHTTP(httptest.NewRecorder(), signedRequest(t, recordCreated("ev6"), now))
+	}()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, signedRequest(t, recordCreated("ev6"), now))
+	if rec.Code != http.StatusOK || calls != 2 {
+		t.Errorf("expected the redelivery to be handled, status=%d calls=%d", rec.Code, calls)
+	}
+}
+
+func TestHandler_Verification(t *testing.T) {
+	h := newTestHandler()
+	h.OnRecordCreated(func(ctx context.Context, event *Event, record RecordEvent) error {
+		t.Error("handler must not run for rejected requests")
+		return nil
+	})
+	now := time.Now()
+
+	tampered := signedRequest(t, recordCreated("ev2"), now)
+	tampered.Header.Set(HeaderSignature, strings.Repeat("0", 64))
+
+	expired := signedRequest(t, recordCreated("ev3"), now.Add(-time.Hour))
+
+	badToken := recordCreated("ev4")
+	badToken["header"].(map[string]any)["token"] = "other"
+
+	unsigned := signedRequest(t, recordCreated("ev5"), now)
+	unsigned.Header.Del(HeaderSignature)
+
+	for name, req := range map[string]*http.Request{
+		"tampered signature": tampered,
+		"expired timestamp":  expired,
+		"invalid token":      signedRequest(t, badToken, now),
+		"missing signature":  unsigned,
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", name, rec.Code)
+		}
+	}
+}
+
+func TestHandler_URLVerification(t *testing.T) {
+	h := newTestHandler()
+	plaintext, _ := json.Marshal(map[string]any{"type": "url_verification", "challenge": "abc", "token": testToken})
+	body, _ := json.Marshal(map[string]string{"encrypt": encrypt(t, plaintext, testEncryptKey)})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body)))
+
+	var resp map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK || resp["challenge"] != "abc" {
+		t.Errorf("status=%d body=%s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandler_PlainSchema1(t *testing.T) {
+	h := NewHandler(Options{VerificationToken: testToken})
+	var flow FlowCompletedEvent
+	h.OnFlowCompleted(func(ctx context.Context, event *Event, payload FlowCompletedEvent) error {
+		flow = payload
+		return nil
+	})
+
+	body := `{"uuid":"u1","token":"verification-token","type":"event_callback","event":{"type":"flow.completed","flow_api_name":"flow_a","instance_id":"i1","status":"succeeded"}}`
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK || flow.InstanceID != "i1" || flow.Status != "succeeded" {
+		t.Errorf("status=%d flow=%+v", rec.Code, flow)
+	}
+}